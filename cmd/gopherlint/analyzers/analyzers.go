@@ -0,0 +1,63 @@
+// Package analyzers lists analyzers of gopherlint.
+package analyzers
+
+import (
+	"slices"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/gopherd/tools/cmd/gopherlint/ctxcheck"
+	"github.com/gopherd/tools/cmd/gopherlint/deadmod"
+	"github.com/gopherd/tools/cmd/gopherlint/earlyreturn"
+	"github.com/gopherd/tools/cmd/gopherlint/enum"
+	"github.com/gopherd/tools/cmd/gopherlint/final"
+	"github.com/gopherd/tools/cmd/gopherlint/finalconst"
+	"github.com/gopherd/tools/cmd/gopherlint/guardedby"
+	"github.com/gopherd/tools/cmd/gopherlint/logcheck"
+	"github.com/gopherd/tools/cmd/gopherlint/nilnil"
+	"github.com/gopherd/tools/cmd/gopherlint/nocopy"
+	"github.com/gopherd/tools/cmd/gopherlint/noescape"
+	"github.com/gopherd/tools/cmd/gopherlint/protomsg"
+	"github.com/gopherd/tools/cmd/gopherlint/pure"
+	"github.com/gopherd/tools/cmd/gopherlint/suppress"
+	"github.com/gopherd/tools/cmd/gopherlint/typednil"
+	"github.com/gopherd/tools/cmd/gopherlint/unhandlederror"
+	"github.com/gopherd/tools/cmd/gopherlint/unusedresult"
+	"github.com/gopherd/tools/cmd/gopherlint/visibility"
+)
+
+// List returns analyzers of gopherlint, excluding the suppress analyzer
+// which checks directives of the others.
+func List() []*analysis.Analyzer {
+	return []*analysis.Analyzer{
+		unusedresult.Analyzer,
+		final.Analyzer,
+		visibility.Analyzer,
+		nocopy.Analyzer,
+		noescape.Analyzer,
+		pure.Analyzer,
+		guardedby.Analyzer,
+		enum.Analyzer,
+		nilnil.Analyzer,
+		unhandlederror.Analyzer,
+		protomsg.Analyzer,
+		logcheck.Analyzer,
+		earlyreturn.Analyzer,
+		typednil.Analyzer,
+		ctxcheck.Analyzer,
+		deadmod.Analyzer,
+		finalconst.Analyzer,
+	}
+}
+
+var all = sync.OnceValue(func() []*analysis.Analyzer {
+	list := List()
+	return append(list, suppress.New(list...))
+})
+
+// All returns analyzers of gopherlint wrapped by suppress.Wrap, followed by
+// the suppress analyzer which checks directives naming them.
+func All() []*analysis.Analyzer {
+	return slices.Clone(all())
+}
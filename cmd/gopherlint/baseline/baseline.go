@@ -0,0 +1,205 @@
+package baseline
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+)
+
+// An Entry identifies findings independent of line numbers: by analyzer,
+// file relative to the baseline file, enclosing function and message. Positions
+// in the message, e.g. reported by -final.verbose, are replaced by base names of
+// their files.
+type Entry struct {
+	Analyzer string `json:"analyzer"`
+	File     string `json:"file"`
+	Function string `json:"function,omitempty"`
+	Message  string `json:"message"`
+	Count    int    `json:"count"` // number of findings identified by the entry
+}
+
+type file struct {
+	Findings []Entry `json:"findings"`
+}
+
+// Baseline is a set of known findings.
+type Baseline struct {
+	dir    string
+	counts map[Entry]int // entries with zero Count
+}
+
+// Load loads baseline file filename.
+func Load(filename string) (*Baseline, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	b, err := newBaseline(filename)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range f.Findings {
+		count := e.Count
+		e.Count = 0
+		b.counts[e] += max(count, 1)
+	}
+	return b, nil
+}
+
+// Write writes findings to baseline file filename.
+func Write(filename string, list []findings.Finding) error {
+	b, err := newBaseline(filename)
+	if err != nil {
+		return err
+	}
+	var keys = newKeys(b.dir)
+	for _, f := range list {
+		b.counts[keys.entry(f)]++
+	}
+	var out = file{Findings: make([]Entry, 0, len(b.counts))}
+	for e, count := range b.counts {
+		e.Count = count
+		out.Findings = append(out.Findings, e)
+	}
+	sort.Slice(out.Findings, func(i, j int) bool {
+		x, y := out.Findings[i], out.Findings[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.Function != y.Function {
+			return x.Function < y.Function
+		}
+		if x.Analyzer != y.Analyzer {
+			return x.Analyzer < y.Analyzer
+		}
+		return x.Message < y.Message
+	})
+	data, err := json.MarshalIndent(out, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+func newBaseline(filename string) (*Baseline, error) {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &Baseline{
+		dir:    filepath.Dir(abs),
+		counts: make(map[Entry]int),
+	}, nil
+}
+
+// Filter returns findings not in the baseline. If an entry identifies more
+// findings than recorded, the findings after the recorded count are new.
+func (b *Baseline) Filter(list []findings.Finding) []findings.Finding {
+	var keys = newKeys(b.dir)
+	var used = make(map[Entry]int)
+	var result []findings.Finding
+	for _, f := range list {
+		e := keys.entry(f)
+		if used[e] < b.counts[e] {
+			used[e]++
+			continue
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// keys computes entries of findings, parsed files are cached to lookup enclosing functions.
+type keys struct {
+	dir   string
+	fset  *token.FileSet
+	files map[string]*ast.File
+}
+
+func newKeys(dir string) *keys {
+	return &keys{
+		dir:   dir,
+		fset:  token.NewFileSet(),
+		files: make(map[string]*ast.File),
+	}
+}
+
+func (k *keys) entry(f findings.Finding) Entry {
+	var e = Entry{
+		Analyzer: f.Analyzer,
+		File:     f.Filename,
+		Function: k.function(f.Filename, f.Line, f.Column),
+		Message:  stripPositions(f.Message),
+	}
+	if rel, err := filepath.Rel(k.dir, f.Filename); err == nil {
+		e.File = filepath.ToSlash(rel)
+	}
+	return e
+}
+
+// posnRegexp matches positions of Go files, e.g. "/a/b.go:12:3" or "C:\a\b.go:12".
+var posnRegexp = regexp.MustCompile(`[^\s()"]+\.go:\d+(:\d+)?`)
+
+// stripPositions replaces positions in message by base names of their files.
+func stripPositions(message string) string {
+	return posnRegexp.ReplaceAllStringFunc(message, func(posn string) string {
+		posn = posn[:strings.LastIndex(posn, ".go:")+len(".go")]
+		return posn[strings.LastIndexAny(posn, `/\`)+1:]
+	})
+}
+
+// function returns name of the top-level function enclosing the position, e.g. "F" or "(*T).M".
+func (k *keys) function(filename string, line, column int) string {
+	file, ok := k.files[filename]
+	if !ok {
+		file, _ = parser.ParseFile(k.fset, filename, nil, parser.SkipObjectResolution)
+		k.files[filename] = file
+	}
+	if file == nil || line <= 0 {
+		return ""
+	}
+	tokFile := k.fset.File(file.Pos())
+	if line > tokFile.LineCount() {
+		return ""
+	}
+	pos := tokFile.LineStart(line) + token.Pos(max(column-1, 0))
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || pos < fn.Pos() || pos >= fn.End() {
+			continue
+		}
+		if fn.Recv == nil || len(fn.Recv.List) == 0 {
+			return fn.Name.Name
+		}
+		return "(" + recvString(fn.Recv.List[0].Type) + ")." + fn.Name.Name
+	}
+	return ""
+}
+
+func recvString(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + recvString(x.X)
+	case *ast.Ident:
+		return x.Name
+	case *ast.IndexExpr:
+		return recvString(x.X)
+	case *ast.IndexListExpr:
+		return recvString(x.X)
+	case *ast.ParenExpr:
+		return recvString(x.X)
+	}
+	return ""
+}
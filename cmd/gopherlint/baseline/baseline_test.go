@@ -0,0 +1,174 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+)
+
+const source = `package a
+
+func F() {
+	_ = 1
+	_ = 2
+}
+
+type T struct{}
+
+func (*T) M() {
+	_ = 3
+}
+`
+
+// shifted is source with lines inserted before all findings.
+const shifted = `package a
+
+import "fmt"
+
+func F() {
+	_ = 1
+	_ = 2
+}
+
+type T struct{}
+
+func (*T) M() {
+	fmt.Println()
+	_ = 3
+}
+`
+
+func TestStripPositions(t *testing.T) {
+	for _, tt := range []struct {
+		message, want string
+	}{
+		{"no position", "no position"},
+		{"declared here /a/b/c.go:12:3", "declared here c.go"},
+		{`(directive "@mod:final" declared here /a/b.go:12:3)`, `(directive "@mod:final" declared here b.go)`},
+		{`declared here C:\a\b.go:12:3`, "declared here b.go"},
+		{"see a.go:1 and b.go:2:3", "see a.go and b.go"},
+		{"version 1:2:3", "version 1:2:3"},
+	} {
+		if got := stripPositions(tt.message); got != tt.want {
+			t.Errorf("stripPositions(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(filename, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	finding := func(line int, message string) findings.Finding {
+		return findings.Finding{Analyzer: "x", Filename: filename, Line: line, Column: 2, Message: message}
+	}
+	baselineFile := filepath.Join(dir, "baseline.json")
+	if err := Write(baselineFile, []findings.Finding{
+		finding(4, "one"),
+		finding(5, "one"),
+		finding(11, "declared here "+filename+":11:2"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Load(baselineFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[Entry]int{
+		{Analyzer: "x", File: "a.go", Function: "F", Message: "one"}:                     2,
+		{Analyzer: "x", File: "a.go", Function: "(*T).M", Message: "declared here a.go"}: 1,
+	}
+	if !reflect.DeepEqual(b.counts, want) {
+		t.Errorf("loaded %v, want %v", b.counts, want)
+	}
+
+	// Lines of findings change after the baseline was written.
+	if err := os.WriteFile(filename, []byte(shifted), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		list []findings.Finding
+		want []findings.Finding
+	}{
+		{
+			name: "same findings",
+			list: []findings.Finding{finding(6, "one"), finding(7, "one"), finding(14, "declared here "+filename+":14:2")},
+		},
+		{
+			name: "duplicates more than recorded",
+			list: []findings.Finding{finding(6, "one"), finding(6, "one"), finding(7, "one")},
+			want: []findings.Finding{finding(7, "one")},
+		},
+		{
+			name: "new message",
+			list: []findings.Finding{finding(6, "two")},
+			want: []findings.Finding{finding(6, "two")},
+		},
+		{
+			name: "another function",
+			list: []findings.Finding{finding(14, "one")},
+			want: []findings.Finding{finding(14, "one")},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Filter(tt.list); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		name    string
+		content string
+		want    map[Entry]int
+		wantErr bool
+	}{
+		{
+			name:    "count omitted",
+			content: `{"findings": [{"analyzer": "x", "file": "a.go", "message": "m"}]}`,
+			want:    map[Entry]int{{Analyzer: "x", File: "a.go", Message: "m"}: 1},
+		},
+		{
+			name: "duplicate entries",
+			content: `{"findings": [
+				{"analyzer": "x", "file": "a.go", "message": "m", "count": 2},
+				{"analyzer": "x", "file": "a.go", "message": "m", "count": 3}
+			]}`,
+			want: map[Entry]int{{Analyzer: "x", File: "a.go", Message: "m"}: 5},
+		},
+		{
+			name:    "invalid",
+			content: `{"findings": {}}`,
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, "baseline.json")
+			if err := os.WriteFile(filename, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			b, err := Load(filename)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Load succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(b.counts, tt.want) {
+				t.Errorf("loaded %v, want %v", b.counts, tt.want)
+			}
+		})
+	}
+}
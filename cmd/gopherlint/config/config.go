@@ -0,0 +1,299 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"gopkg.in/yaml.v3"
+)
+
+// Filenames lists names of configuration files in lookup order.
+var Filenames = []string{".gopherlint.yaml", ".gopherlint.yml", ".gopherlint.json"}
+
+// Config represents a gopherlint configuration file, e.g.
+//
+//	analyzers: [final, unusedresult]
+//	settings:
+//	  final:
+//	    verbose: 1
+//	  unusedresult:
+//	    types: ["*github.com/gopherd/log.Context"]
+//	overrides:
+//	  - path: internal/legacy/...
+//	    disable: [final]
+//	exclude:
+//	  - "**/*_mock.go"
+//	generated:
+//	  - "**/*_gen.go"
+//
+// Paths are slash-separated and relative to the directory of the file.
+type Config struct {
+	// Analyzers lists enabled analyzers, all analyzers are enabled if empty.
+	Analyzers []string `yaml:"analyzers" json:"analyzers"`
+	// Settings maps analyzer names to values of their flags. A list value
+	// is joined by commas.
+	Settings map[string]map[string]any `yaml:"settings" json:"settings"`
+	// Overrides enables or disables analyzers in directories, later
+	// overrides take precedence over earlier ones.
+	Overrides []Override `yaml:"overrides" json:"overrides"`
+	// Exclude lists glob patterns of files whose diagnostics are dropped,
+	// "**" matches any number of directories.
+	Exclude []string `yaml:"exclude" json:"exclude"`
+	// Generated lists glob patterns of generated files in addition to files
+	// with a "// Code generated ... DO NOT EDIT." comment.
+	Generated []string `yaml:"generated" json:"generated"`
+
+	dir string // directory of the configuration file
+}
+
+// Override enables or disables analyzers in directories matching Path. A
+// trailing "/..." in Path also matches all sub-directories.
+type Override struct {
+	Path    string   `yaml:"path" json:"path"`
+	Enable  []string `yaml:"enable" json:"enable"`
+	Disable []string `yaml:"disable" json:"disable"`
+}
+
+// Find looks up a configuration file in dir and its parent directories up
+// to the root of the module (the first directory containing go.mod). It
+// returns an empty string if no configuration file is found.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, name := range Filenames {
+			filename := filepath.Join(dir, name)
+			if _, err := os.Stat(filename); err == nil {
+				return filename, nil
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return "", nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load loads the configuration file filename, it's decoded as JSON if the
+// extension is ".json", otherwise as YAML.
+func Load(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var c = new(Config)
+	if filepath.Ext(filename) == ".json" {
+		err = json.Unmarshal(data, c)
+	} else {
+		err = yaml.Unmarshal(data, c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	c.dir = filepath.Dir(abs)
+	return c, nil
+}
+
+// Apply sets flags of analyzers by settings and returns analyzers which
+// should be run: the enabled analyzers and analyzers enabled by overrides.
+func (c *Config) Apply(analyzers []*analysis.Analyzer) ([]*analysis.Analyzer, error) {
+	var byName = make(map[string]*analysis.Analyzer)
+	for _, a := range analyzers {
+		byName[a.Name] = a
+	}
+	var check = func(names []string) error {
+		for _, name := range names {
+			if byName[name] == nil {
+				return fmt.Errorf("unknown analyzer %q", name)
+			}
+		}
+		return nil
+	}
+	if err := check(c.Analyzers); err != nil {
+		return nil, err
+	}
+	var run = make(map[string]bool)
+	for _, name := range c.Analyzers {
+		run[name] = true
+	}
+	for _, o := range c.Overrides {
+		if err := check(o.Enable); err != nil {
+			return nil, fmt.Errorf("override %q: %w", o.Path, err)
+		}
+		if err := check(o.Disable); err != nil {
+			return nil, fmt.Errorf("override %q: %w", o.Path, err)
+		}
+		for _, name := range o.Enable {
+			run[name] = true
+		}
+	}
+	for name, settings := range c.Settings {
+		a, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("settings of unknown analyzer %q", name)
+		}
+		for flagName, value := range settings {
+			if a.Flags.Lookup(flagName) == nil {
+				return nil, fmt.Errorf("unknown setting %q of analyzer %q", flagName, name)
+			}
+			if err := a.Flags.Set(flagName, flagValue(value)); err != nil {
+				return nil, fmt.Errorf("setting %q of analyzer %q: %w", flagName, name, err)
+			}
+		}
+	}
+	if len(c.Analyzers) == 0 {
+		return analyzers, nil
+	}
+	var result []*analysis.Analyzer
+	for _, a := range analyzers {
+		if run[a.Name] {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func flagValue(value any) string {
+	switch x := value.(type) {
+	case []any:
+		var items = make([]string, 0, len(x))
+		for _, item := range x {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// Enabled reports whether analyzer name is enabled for file filename.
+func (c *Config) Enabled(name, filename string) bool {
+	var enabled = len(c.Analyzers) == 0 || slices.Contains(c.Analyzers, name)
+	rel, ok := c.rel(filename)
+	if !ok {
+		return enabled
+	}
+	dir := filepath.ToSlash(filepath.Dir(rel))
+	for _, o := range c.Overrides {
+		if !matchDir(strings.Trim(o.Path, "/"), dir) {
+			continue
+		}
+		if slices.Contains(o.Enable, name) {
+			enabled = true
+		}
+		if slices.Contains(o.Disable, name) {
+			enabled = false
+		}
+	}
+	return enabled
+}
+
+// Excluded reports whether file filename is excluded.
+func (c *Config) Excluded(filename string) bool {
+	return c.match(c.Exclude, filename)
+}
+
+// IsGenerated reports whether file filename matches one of generated file patterns.
+func (c *Config) IsGenerated(filename string) bool {
+	return c.match(c.Generated, filename)
+}
+
+func (c *Config) match(patterns []string, filename string) bool {
+	rel, ok := c.rel(filename)
+	if !ok {
+		return false
+	}
+	for _, pattern := range patterns {
+		if matchGlob(strings.Split(pattern, "/"), strings.Split(rel, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Wrap wraps analyzers so that their diagnostics in excluded files or in
+// directories where they are disabled are dropped.
+func (c *Config) Wrap(analyzers ...*analysis.Analyzer) {
+	for _, a := range analyzers {
+		var name = a.Name
+		var runFunc = a.Run
+		a.Run = func(pass *analysis.Pass) (interface{}, error) {
+			report := pass.Report
+			pass.Report = func(diag analysis.Diagnostic) {
+				filename := pass.Fset.Position(diag.Pos).Filename
+				if !c.Excluded(filename) && c.Enabled(name, filename) {
+					report(diag)
+				}
+			}
+			defer func() { pass.Report = report }()
+			return runFunc(pass)
+		}
+	}
+}
+
+// rel returns slash-separated path of filename relative to directory of the configuration file.
+func (c *Config) rel(filename string) (string, bool) {
+	if filename == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(c.dir, filename)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func matchDir(pattern, dir string) bool {
+	if pattern == "..." {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		prefix = path.Clean(prefix)
+		return prefix == "." || dir == prefix || strings.HasPrefix(dir, prefix+"/")
+	}
+	return dir == path.Clean(pattern)
+}
+
+// matchGlob matches path elements against pattern elements, "**" matches
+// zero or more elements and other elements are matched by filepath.Match.
+func matchGlob(pattern, elems []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(elems); i++ {
+				if matchGlob(pattern[1:], elems[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(elems) == 0 {
+			return false
+		}
+		if ok, _ := filepath.Match(pattern[0], elems[0]); !ok {
+			return false
+		}
+		pattern, elems = pattern[1:], elems[1:]
+	}
+	return len(elems) == 0
+}
@@ -0,0 +1,339 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestMatchGlob(t *testing.T) {
+	for _, tt := range []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"a.go", "a.go", true},
+		{"*.go", "a.go", true},
+		{"*.go", "x/a.go", false},
+		{"x/*.go", "x/a.go", true},
+		{"**/*.go", "a.go", true},
+		{"**/*.go", "x/y/a.go", true},
+		{"**/*_mock.go", "x/a_mock.go", true},
+		{"**/*_mock.go", "x/a.go", false},
+		{"x/**", "x/y/a.go", true},
+		{"x/**", "y/a.go", false},
+		{"x/**/a.go", "x/a.go", true},
+		{"x/**/a.go", "x/y/z/a.go", true},
+		{"x/**/a.go", "x/y/z/b.go", false},
+		{"x", "x/a.go", false},
+	} {
+		got := matchGlob(strings.Split(tt.pattern, "/"), strings.Split(tt.path, "/"))
+		if got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %t, want %t", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMatchDir(t *testing.T) {
+	for _, tt := range []struct {
+		pattern, dir string
+		want         bool
+	}{
+		{"...", ".", true},
+		{"...", "x/y", true},
+		{"./...", "x", true},
+		{"x", "x", true},
+		{"x", "x/y", false},
+		{"x/", "x", true},
+		{"x/...", "x", true},
+		{"x/...", "x/y", true},
+		{"x/...", "xy", false},
+		{"x/y/...", "x", false},
+		{".", ".", true},
+	} {
+		if got := matchDir(tt.pattern, tt.dir); got != tt.want {
+			t.Errorf("matchDir(%q, %q) = %t, want %t", tt.pattern, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	dir := filepath.FromSlash("/repo")
+	for _, tt := range []struct {
+		name     string
+		config   Config
+		analyzer string
+		filename string
+		want     bool
+	}{
+		{
+			name:     "all enabled",
+			analyzer: "final",
+			filename: "a/a.go",
+			want:     true,
+		},
+		{
+			name:     "not listed",
+			config:   Config{Analyzers: []string{"pure"}},
+			analyzer: "final",
+			filename: "a/a.go",
+			want:     false,
+		},
+		{
+			name:     "listed",
+			config:   Config{Analyzers: []string{"pure", "final"}},
+			analyzer: "final",
+			filename: "a/a.go",
+			want:     true,
+		},
+		{
+			name:     "disabled in directory",
+			config:   Config{Overrides: []Override{{Path: "a/...", Disable: []string{"final"}}}},
+			analyzer: "final",
+			filename: "a/b/a.go",
+			want:     false,
+		},
+		{
+			name:     "disabled in other directory",
+			config:   Config{Overrides: []Override{{Path: "a/...", Disable: []string{"final"}}}},
+			analyzer: "final",
+			filename: "b/a.go",
+			want:     true,
+		},
+		{
+			name:     "disabled in other analyzer",
+			config:   Config{Overrides: []Override{{Path: "a/...", Disable: []string{"pure"}}}},
+			analyzer: "final",
+			filename: "a/a.go",
+			want:     true,
+		},
+		{
+			name:     "enabled in directory",
+			config:   Config{Analyzers: []string{"pure"}, Overrides: []Override{{Path: "a", Enable: []string{"final"}}}},
+			analyzer: "final",
+			filename: "a/a.go",
+			want:     true,
+		},
+		{
+			name:     "enabled not in sub-directory",
+			config:   Config{Analyzers: []string{"pure"}, Overrides: []Override{{Path: "a", Enable: []string{"final"}}}},
+			analyzer: "final",
+			filename: "a/b/a.go",
+			want:     false,
+		},
+		{
+			name: "later override takes precedence",
+			config: Config{Overrides: []Override{
+				{Path: "a/...", Disable: []string{"final"}},
+				{Path: "a/b/...", Enable: []string{"final"}},
+			}},
+			analyzer: "final",
+			filename: "a/b/a.go",
+			want:     true,
+		},
+		{
+			name: "later override takes precedence over enable",
+			config: Config{Overrides: []Override{
+				{Path: "a/b/...", Enable: []string{"final"}},
+				{Path: "...", Disable: []string{"final"}},
+			}},
+			analyzer: "final",
+			filename: "a/b/a.go",
+			want:     false,
+		},
+		{
+			name:     "outside of configuration directory",
+			config:   Config{Overrides: []Override{{Path: "...", Disable: []string{"final"}}}},
+			analyzer: "final",
+			filename: "../other/a.go",
+			want:     true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.config
+			c.dir = dir
+			if got := c.Enabled(tt.analyzer, filepath.Join(dir, filepath.FromSlash(tt.filename))); got != tt.want {
+				t.Errorf("Enabled(%q, %q) = %t, want %t", tt.analyzer, tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	dir := filepath.FromSlash("/repo")
+	c := &Config{
+		Exclude:   []string{"**/*_mock.go", "vendor/**"},
+		Generated: []string{"gen/*.go"},
+		dir:       dir,
+	}
+	for _, tt := range []struct {
+		filename            string
+		excluded, generated bool
+	}{
+		{"a/a.go", false, false},
+		{"a/a_mock.go", true, false},
+		{"a_mock.go", true, false},
+		{"vendor/x/a.go", true, false},
+		{"gen/a.go", false, true},
+		{"gen/x/a.go", false, false},
+		{"../other/a_mock.go", false, false},
+	} {
+		filename := filepath.Join(dir, filepath.FromSlash(tt.filename))
+		if got := c.Excluded(filename); got != tt.excluded {
+			t.Errorf("Excluded(%q) = %t, want %t", tt.filename, got, tt.excluded)
+		}
+		if got := c.IsGenerated(filename); got != tt.generated {
+			t.Errorf("IsGenerated(%q) = %t, want %t", tt.filename, got, tt.generated)
+		}
+	}
+}
+
+func newAnalyzer(name string) *analysis.Analyzer {
+	a := &analysis.Analyzer{Name: name}
+	a.Flags.Init(name, flag.ContinueOnError)
+	a.Flags.String("types", "", "")
+	a.Flags.Int("verbose", 0, "")
+	return a
+}
+
+func TestApply(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		config  Config
+		want    []string
+		flags   map[string]string // flags of analyzer a
+		wantErr string
+	}{
+		{
+			name: "all",
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name:   "listed",
+			config: Config{Analyzers: []string{"c", "a"}},
+			want:   []string{"a", "c"},
+		},
+		{
+			name: "enabled by override",
+			config: Config{
+				Analyzers: []string{"a"},
+				Overrides: []Override{{Path: "x/...", Enable: []string{"b"}}},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "disabled by override still runs",
+			config: Config{
+				Analyzers: []string{"a"},
+				Overrides: []Override{{Path: "x/...", Disable: []string{"a"}}},
+			},
+			want: []string{"a"},
+		},
+		{
+			name: "settings",
+			config: Config{Settings: map[string]map[string]any{
+				"a": {"types": []any{"x.T", "*y.U"}, "verbose": 1},
+			}},
+			want:  []string{"a", "b", "c"},
+			flags: map[string]string{"types": "x.T,*y.U", "verbose": "1"},
+		},
+		{
+			name:    "unknown analyzer",
+			config:  Config{Analyzers: []string{"d"}},
+			wantErr: `unknown analyzer "d"`,
+		},
+		{
+			name:    "unknown analyzer of override",
+			config:  Config{Overrides: []Override{{Path: "x", Disable: []string{"d"}}}},
+			wantErr: `override "x": unknown analyzer "d"`,
+		},
+		{
+			name:    "settings of unknown analyzer",
+			config:  Config{Settings: map[string]map[string]any{"d": {"types": "x"}}},
+			wantErr: `settings of unknown analyzer "d"`,
+		},
+		{
+			name:    "unknown setting",
+			config:  Config{Settings: map[string]map[string]any{"a": {"nosuch": "x"}}},
+			wantErr: `unknown setting "nosuch" of analyzer "a"`,
+		},
+		{
+			name:    "invalid setting",
+			config:  Config{Settings: map[string]map[string]any{"a": {"verbose": "x"}}},
+			wantErr: `setting "verbose" of analyzer "a"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzers := []*analysis.Analyzer{newAnalyzer("a"), newAnalyzer("b"), newAnalyzer("c")}
+			result, err := tt.config.Apply(analyzers)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Apply error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, a := range result {
+				names = append(names, a.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Apply = %v, want %v", names, tt.want)
+			}
+			for name, want := range tt.flags {
+				if got := analyzers[0].Flags.Lookup(name).Value.String(); got != want {
+					t.Errorf("flag %s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	mkdir := func(elems ...string) string {
+		dir := filepath.Join(append([]string{root}, elems...)...)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	write := func(filename string) string {
+		if err := os.WriteFile(filename, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+	// root/.gopherlint.yaml configures the repository, which contains
+	// module root/mod without configuration, and root/pkg of the root module.
+	config := write(filepath.Join(root, Filenames[0]))
+	mkdir(".git")
+	write(filepath.Join(root, "go.mod"))
+	write(filepath.Join(mkdir("mod"), "go.mod"))
+	write(filepath.Join(mkdir("nested", "mod"), "go.mod"))
+	nested := write(filepath.Join(root, "nested", "mod", Filenames[2]))
+
+	for _, tt := range []struct {
+		dir  string
+		want string
+	}{
+		{root, config},
+		{mkdir("pkg", "sub"), config},
+		{mkdir("mod", "sub"), ""},
+		{mkdir("nested", "mod", "sub"), nested},
+	} {
+		got, err := Find(tt.dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Find(%s) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
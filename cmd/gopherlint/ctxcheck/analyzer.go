@@ -0,0 +1,259 @@
+package ctxcheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/ctrlflow"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/cfg"
+
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const Doc = `check for goroutines and cancel functions which leak contexts.
+
+This analyzer reports go statements in functions with a named context.Context
+parameter whose goroutines do not receive the context or a context derived
+from it, by an argument or a reference in the function literal, and cancel
+functions returned by context.WithCancel, WithTimeout, WithDeadline and their
+Cause variants which are not called, or used otherwise, on all paths to a
+return of the function.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "ctxcheck",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer, ctrlflow.Analyzer},
+	Run:      run,
+}
+
+// withCancelFuncs lists functions of package context returning a cancel function.
+var withCancelFuncs = map[string]bool{
+	"WithCancel":        true,
+	"WithCancelCause":   true,
+	"WithTimeout":       true,
+	"WithTimeoutCause":  true,
+	"WithDeadline":      true,
+	"WithDeadlineCause": true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	cfgs := pass.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs)
+
+	inspect.Preorder([]ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+	}, func(n ast.Node) {
+		var typ *ast.FuncType
+		var body *ast.BlockStmt
+		var g *cfg.CFG
+		switch x := n.(type) {
+		case *ast.FuncDecl:
+			typ, body, g = x.Type, x.Body, cfgs.FuncDecl(x)
+		case *ast.FuncLit:
+			typ, body, g = x.Type, x.Body, cfgs.FuncLit(x)
+		}
+		if body == nil {
+			return
+		}
+		if ctxParams := contextParams(pass, typ); len(ctxParams) > 0 {
+			checkGoroutines(pass, body, ctxParams)
+		}
+		if g != nil {
+			checkCancels(pass, body, g)
+		}
+	})
+	return nil, nil
+}
+
+func isContext(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// contextParams returns context.Context parameters of a function, blank
+// parameters are not returned since they can't be passed to goroutines.
+func contextParams(pass *analysis.Pass, typ *ast.FuncType) map[types.Object]bool {
+	var params = make(map[types.Object]bool)
+	for _, field := range typ.Params.List {
+		for _, name := range field.Names {
+			if name.Name == "_" {
+				continue
+			}
+			if obj := pass.TypesInfo.Defs[name]; obj != nil && isContext(obj.Type()) {
+				params[obj] = true
+			}
+		}
+	}
+	return params
+}
+
+// references reports whether node references any object of objects.
+func references(pass *analysis.Pass, node ast.Node, objects map[types.Object]bool) bool {
+	var found bool
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && objects[pass.TypesInfo.Uses[ident]] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkGoroutines reports go statements in body which don't receive a
+// context derived from ctxParams. Function literals with their own context
+// parameters are checked separately.
+func checkGoroutines(pass *analysis.Pass, body *ast.BlockStmt, ctxParams map[types.Object]bool) {
+	var derived = make(map[types.Object]bool)
+	for obj := range ctxParams {
+		derived[obj] = true
+	}
+	var derive = func(lhs []ast.Expr, rhs []ast.Expr) {
+		for i, expr := range lhs {
+			ident, ok := expr.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			obj := pass.TypesInfo.ObjectOf(ident)
+			if obj == nil || !isContext(obj.Type()) {
+				continue
+			}
+			value := rhs[0]
+			if len(rhs) == len(lhs) {
+				value = rhs[i]
+			}
+			if references(pass, value, derived) {
+				derived[obj] = true
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			return len(contextParams(pass, x.Type)) == 0
+		case *ast.AssignStmt:
+			derive(x.Lhs, x.Rhs)
+		case *ast.ValueSpec:
+			if len(x.Values) > 0 {
+				var lhs = make([]ast.Expr, len(x.Names))
+				for i, name := range x.Names {
+					lhs[i] = name
+				}
+				derive(lhs, x.Values)
+			}
+		case *ast.GoStmt:
+			if !references(pass, x.Call, derived) {
+				pass.Reportf(x.Pos(), "goroutine does not receive the context of the enclosing function")
+			}
+		}
+		return true
+	})
+}
+
+// checkCancels reports cancel functions created in body which are not used
+// on all paths to a return.
+func checkCancels(pass *analysis.Pass, body *ast.BlockStmt, g *cfg.CFG) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		var lhs []ast.Expr
+		var rhs []ast.Expr
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			return false // checked separately
+		case *ast.AssignStmt:
+			lhs, rhs = x.Lhs, x.Rhs
+		case *ast.ValueSpec:
+			for _, name := range x.Names {
+				lhs = append(lhs, name)
+			}
+			rhs = x.Values
+		default:
+			return true
+		}
+		if len(lhs) != 2 || len(rhs) != 1 {
+			return true
+		}
+		call, ok := util.Unparen(rhs[0]).(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn, _, _ := util.GetFunc(pass, util.Unparen(call.Fun))
+		if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != "context" || !withCancelFuncs[fn.Name()] {
+			return true
+		}
+		ident, ok := lhs[1].(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if ident.Name == "_" {
+			pass.Reportf(ident.Pos(), "the cancel function returned by context.%s is discarded", fn.Name())
+			return true
+		}
+		obj := pass.TypesInfo.ObjectOf(ident)
+		if ret := leakingReturn(pass, g, n, obj); ret != nil {
+			pass.Report(analysis.Diagnostic{
+				Pos:     ident.Pos(),
+				Message: "the cancel function " + ident.Name + " returned by context." + fn.Name() + " is not called on all paths",
+				Related: []analysis.RelatedInformation{{
+					Pos:     ret.Pos(),
+					Message: "this return may be reached without calling " + ident.Name,
+				}},
+			})
+		}
+		return true
+	})
+}
+
+// leakingReturn returns a node exiting the function, reachable from stmt
+// without using obj, or nil.
+func leakingReturn(pass *analysis.Pass, g *cfg.CFG, stmt ast.Node, obj types.Object) ast.Node {
+	var uses = func(n ast.Node) bool {
+		return references(pass, n, map[types.Object]bool{obj: true})
+	}
+	var seen = make(map[*cfg.Block]bool)
+	var search func(b *cfg.Block, nodes []ast.Node) ast.Node
+	search = func(b *cfg.Block, nodes []ast.Node) ast.Node {
+		for _, n := range nodes {
+			if uses(n) {
+				return nil
+			}
+		}
+		if ret := b.Return(); ret != nil {
+			return ret
+		}
+		if len(b.Succs) == 0 {
+			if len(b.Nodes) > 0 {
+				if last, ok := b.Nodes[len(b.Nodes)-1].(ast.Stmt); ok && util.IsInterruptedStmt(pass, last) {
+					return nil // e.g. panic
+				}
+				return b.Nodes[len(b.Nodes)-1]
+			}
+			return nil
+		}
+		for _, succ := range b.Succs {
+			if seen[succ] || !succ.Live {
+				continue
+			}
+			seen[succ] = true
+			if ret := search(succ, succ.Nodes); ret != nil {
+				return ret
+			}
+		}
+		return nil
+	}
+	for _, b := range g.Blocks {
+		for i, n := range b.Nodes {
+			if n == stmt || n.Pos() <= stmt.Pos() && stmt.End() <= n.End() && isDecl(n) {
+				return search(b, b.Nodes[i+1:])
+			}
+		}
+	}
+	return nil
+}
+
+func isDecl(n ast.Node) bool {
+	_, ok := n.(*ast.DeclStmt)
+	return ok
+}
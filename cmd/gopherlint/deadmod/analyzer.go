@@ -0,0 +1,110 @@
+package deadmod
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/gopherd/tools/cmd/gopherlint/modifier"
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const Doc = `check for //@mod: directives which have no effect.
+
+This analyzer reports
+
+  - directives with a name unknown to gopherlint, e.g. a misspelled
+    "//@mod:finall". Names used by other tools may be added by flag
+    -deadmod.names.
+  - @mod:nilnil on functions not returning (T, error), whose results it
+    is about.
+
+Only directives declared on the function declaration itself are checked for
+effect, directives inherited from the file or package scope are not.
+
+Variables annotated by @mod:final which could be declared as constants are
+reported by the finalconst analyzer.`
+
+// known holds names of directives used by gopherlint analyzers.
+var known = map[string]bool{
+	"final":        true,
+	"internal":     true,
+	"nocopy":       true,
+	"noescape":     true,
+	"pure":         true,
+	"sideeffect":   true,
+	"guardedby":    true,
+	"enum":         true,
+	"nilnil":       true,
+	"lint-disable": true,
+}
+
+const negation = "!"
+
+var extraNames util.StringSetFlag
+
+func init() {
+	Analyzer.Flags.Var(&extraNames, "names",
+		"comma-separated list of directive names used by other tools")
+}
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "deadmod",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer, modifier.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	modifiers := pass.ResultOf[modifier.Analyzer].(*modifier.Result)
+
+	for _, file := range pass.Files {
+		for _, m := range modifier.Scan(pass, file) {
+			name := strings.TrimPrefix(m.Name(), negation)
+			if !known[name] && !extraNames[name] {
+				pass.Reportf(position(pass, file, m), "unknown directive @mod:%s", name)
+			}
+		}
+	}
+
+	inspect.Preorder([]ast.Node{
+		(*ast.FuncDecl)(nil),
+	}, func(n ast.Node) {
+		x := n.(*ast.FuncDecl)
+		fn, ok := pass.TypesInfo.Defs[x.Name].(*types.Func)
+		if !ok {
+			return
+		}
+		results := fn.Type().(*types.Signature).Results()
+		if m, ok := modifiers.Find(fn, "nilnil"); ok && declaredIn(pass, m, x.Doc) &&
+			(results.Len() != 2 || !util.IsError(results.At(1).Type())) {
+			pass.Reportf(x.Name.Pos(), "@mod:nilnil has no effect on %s which does not return (T, error)", x.Name.Name)
+		}
+	})
+	return nil, nil
+}
+
+// position returns the position of modifier m declared in file.
+func position(pass *analysis.Pass, file *ast.File, m modifier.Modifier) token.Pos {
+	return pass.Fset.File(file.Pos()).LineStart(m.Position.Line)
+}
+
+// declaredIn reports whether modifier m is declared in one of comment groups.
+func declaredIn(pass *analysis.Pass, m modifier.Modifier, groups ...*ast.CommentGroup) bool {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		start, end := pass.Fset.Position(group.Pos()), pass.Fset.Position(group.End())
+		if m.Position.Filename == start.Filename && start.Line <= m.Position.Line && m.Position.Line <= end.Line {
+			return true
+		}
+	}
+	return false
+}
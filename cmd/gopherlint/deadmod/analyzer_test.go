@@ -0,0 +1,21 @@
+package deadmod_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/gopherd/tools/cmd/gopherlint/deadmod"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata, err := filepath.Abs("../testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deadmod.Analyzer.Flags.Set("names", "custom"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, deadmod.Analyzer, "deadmod/...")
+}
@@ -0,0 +1,157 @@
+// Package diff parses unified diffs to find lines changed by them.
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+)
+
+// Changes holds lines added or modified by a diff, keyed by absolute filename.
+type Changes struct {
+	files map[string]map[int]bool
+}
+
+// Git returns changes of git diff of revisions, e.g. "main...HEAD", or
+// changes of the working tree since revision if it's a single revision.
+// Paths are resolved relative to the top-level directory of the repository.
+func Git(revisions string) (*Changes, error) {
+	root, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	out, err := git("diff", "--no-color", "--no-ext-diff", "--unified=0", revisions, "--")
+	if err != nil {
+		return nil, err
+	}
+	return Parse(strings.NewReader(out), strings.TrimSpace(root))
+}
+
+// TopLevel returns the top-level directory of the git repository of the
+// current directory, or dir if not in a git repository.
+func TopLevel(dir string) string {
+	root, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return dir
+	}
+	return strings.TrimSpace(root)
+}
+
+func git(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Parse parses unified diff from r. Paths of files in the diff are relative
+// to dir, a prefix "b/" of the new path added by git is removed.
+func Parse(r io.Reader, dir string) (*Changes, error) {
+	var c = &Changes{files: make(map[string]map[int]bool)}
+	var lines map[int]bool  // changed lines of the current file, nil if deleted
+	var line, remaining int // line number in the new file and lines remaining in hunk
+	var s = bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+	for n := 1; s.Scan(); n++ {
+		text := s.Text()
+		if remaining > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				if lines != nil {
+					lines[line] = true
+				}
+				line++
+				remaining--
+			case strings.HasPrefix(text, " "), text == "":
+				line++
+				remaining--
+			case strings.HasPrefix(text, "-"), strings.HasPrefix(text, `\`):
+			default:
+				return nil, fmt.Errorf("diff:%d: unexpected line in hunk: %q", n, text)
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			name := strings.TrimPrefix(text, "+++ ")
+			if i := strings.IndexByte(name, '\t'); i >= 0 {
+				name = name[:i] // timestamp
+			}
+			if name == "/dev/null" {
+				lines = nil
+				continue
+			}
+			if unquoted, err := strconv.Unquote(name); err == nil {
+				name = unquoted
+			}
+			name = strings.TrimPrefix(name, "b/")
+			if !filepath.IsAbs(name) {
+				name = filepath.Join(dir, filepath.FromSlash(name))
+			}
+			name = filepath.Clean(name)
+			if c.files[name] == nil {
+				c.files[name] = make(map[int]bool)
+			}
+			lines = c.files[name]
+		case strings.HasPrefix(text, "@@ "):
+			start, count, err := parseHunk(text)
+			if err != nil {
+				return nil, fmt.Errorf("diff:%d: %w", n, err)
+			}
+			line, remaining = start, count
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseHunk parses the new range of hunk header "@@ -l,s +l,s @@".
+func parseHunk(header string) (start, count int, err error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, fmt.Errorf("malformed hunk header %q", header)
+	}
+	s, c, hasCount := strings.Cut(fields[2][1:], ",")
+	if start, err = strconv.Atoi(s); err != nil {
+		return 0, 0, fmt.Errorf("malformed hunk header %q", header)
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(c); err != nil {
+			return 0, 0, fmt.Errorf("malformed hunk header %q", header)
+		}
+	}
+	return start, count, nil
+}
+
+// Changed reports whether line of file filename is changed.
+func (c *Changes) Changed(filename string, line int) bool {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	return c.files[filepath.Clean(filename)][line]
+}
+
+// Filter returns findings on changed lines.
+func (c *Changes) Filter(list []findings.Finding) []findings.Finding {
+	var result []findings.Finding
+	for _, f := range list {
+		if c.Changed(f.Filename, f.Line) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
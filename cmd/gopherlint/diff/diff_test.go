@@ -0,0 +1,177 @@
+package diff
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	dir := filepath.FromSlash("/repo")
+	for _, tt := range []struct {
+		name string
+		diff string
+		want map[string][]int // changed lines keyed by slash-separated path relative to dir
+	}{
+		{
+			name: "new file",
+			diff: `diff --git a/a.go b/a.go
+new file mode 100644
+index 0000000..1111111
+--- /dev/null
++++ b/a.go
+@@ -0,0 +1,3 @@
++package a
++
++var x = 1
+`,
+			want: map[string][]int{"a.go": {1, 2, 3}},
+		},
+		{
+			name: "omitted counts",
+			diff: `--- a/a.go
++++ b/a.go
+@@ -1 +1 @@
+-package a
++package b
+@@ -10 +10,2 @@ func f() {
+-	x = 1
++	x = 2
++	y = 3
+`,
+			want: map[string][]int{"a.go": {1, 10, 11}},
+		},
+		{
+			name: "context lines",
+			diff: `--- a/a.go
++++ b/a.go
+@@ -3,4 +3,5 @@
+ func f() {
+-	x = 1
++	x = 2
++	y = 3
+
+ }
+`,
+			want: map[string][]int{"a.go": {4, 5}},
+		},
+		{
+			name: "renamed file",
+			diff: `diff --git a/old/a.go b/new/a.go
+similarity index 90%
+rename from old/a.go
+rename to new/a.go
+index 1111111..2222222 100644
+--- a/old/a.go
++++ b/new/a.go
+@@ -2,0 +3 @@ package a
++var y = 2
+diff --git a/old/b.go b/new/b.go
+similarity index 100%
+rename from old/b.go
+rename to new/b.go
+`,
+			want: map[string][]int{"new/a.go": {3}},
+		},
+		{
+			name: "deleted file",
+			diff: `diff --git a/a.go b/a.go
+deleted file mode 100644
+index 1111111..0000000
+--- a/a.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package a
+-var x = 1
+--- a/b.go
++++ b/b.go
+@@ -1,0 +2 @@
++var y = 2
+`,
+			want: map[string][]int{"b.go": {2}},
+		},
+		{
+			name: "no newline at end of file",
+			diff: `--- a/a.go
++++ b/a.go
+@@ -1,2 +1,2 @@
+ package a
+-var x = 1
+\ No newline at end of file
++var x = 2
+\ No newline at end of file
+--- a/b.go
++++ b/b.go
+@@ -1 +1 @@
+-package a
+\ No newline at end of file
++package b
+`,
+			want: map[string][]int{"a.go": {2}, "b.go": {1}},
+		},
+		{
+			name: "quoted name and timestamp",
+			diff: "--- a/a b.go\t2024-01-01 00:00:00\n+++ \"b/a b.go\"\t2024-01-01 00:00:01\n@@ -1 +1 @@\n-package a\n+package b\n",
+			want: map[string][]int{"a b.go": {1}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse(strings.NewReader(tt.diff), dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got = make(map[string][]int)
+			for name, lines := range c.files {
+				rel, err := filepath.Rel(dir, name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				rel = filepath.ToSlash(rel)
+				got[rel] = []int{}
+				for line := 1; line <= 20; line++ {
+					if lines[line] {
+						got[rel] = append(got[rel], line)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changed lines %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, diff := range []string{
+		"--- a/a.go\n+++ b/a.go\n@@ -1 +x @@\n",
+		"--- a/a.go\n+++ b/a.go\n@@ -1 @@\n",
+		"--- a/a.go\n+++ b/a.go\n@@ -1,2 +1,2 @@\n package a\n*var x = 1\n",
+	} {
+		if _, err := Parse(strings.NewReader(diff), "/repo"); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", diff)
+		}
+	}
+}
+
+func TestChanged(t *testing.T) {
+	dir := filepath.FromSlash("/repo")
+	c, err := Parse(strings.NewReader("--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-package a\n+package b\n"), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		filename string
+		line     int
+		want     bool
+	}{
+		{"/repo/a.go", 1, true},
+		{"/repo/./a.go", 1, true},
+		{"/repo/a.go", 2, false},
+		{"/repo/b.go", 1, false},
+	} {
+		if got := c.Changed(filepath.FromSlash(tt.filename), tt.line); got != tt.want {
+			t.Errorf("Changed(%s, %d) = %t, want %t", tt.filename, tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package earlyreturn
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const Doc = `check for code which should return early.
+
+This analyzer reports
+
+	if cond {
+		...
+		return
+	} else {
+		...
+	}
+
+where the if block ends with return, break, continue, goto, panic or a
+Fatal/Exit-style call, so the else is unnecessary, and suggests to outdent
+the else block. It also reports happy paths nested by if statements at the
+end of a function or loop body, like
+
+	if a {
+		if b {
+			if c {
+				...
+			}
+		}
+	}
+
+which should be flattened by inverting the conditions and returning or
+continuing early. The nesting depth reported may be controlled using flag
+-earlyreturn.depth.`
+
+var flags struct {
+	depth int
+}
+
+func init() {
+	Analyzer.Flags.IntVar(&flags.depth, "depth", 3, "minimum nesting depth of happy paths to report")
+}
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "earlyreturn",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{
+		(*ast.BlockStmt)(nil),
+		(*ast.CaseClause)(nil),
+		(*ast.CommClause)(nil),
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.ForStmt)(nil),
+		(*ast.RangeStmt)(nil),
+	}, func(n ast.Node) {
+		switch x := n.(type) {
+		case *ast.BlockStmt:
+			checkElse(pass, x.List)
+		case *ast.CaseClause:
+			checkElse(pass, x.Body)
+		case *ast.CommClause:
+			checkElse(pass, x.Body)
+		case *ast.FuncDecl:
+			checkNesting(pass, x.Body, "return")
+		case *ast.FuncLit:
+			checkNesting(pass, x.Body, "return")
+		case *ast.ForStmt:
+			checkNesting(pass, x.Body, "continue")
+		case *ast.RangeStmt:
+			checkNesting(pass, x.Body, "continue")
+		}
+	})
+	return nil, nil
+}
+
+// checkElse reports if statements in list whose else is unnecessary.
+func checkElse(pass *analysis.Pass, list []ast.Stmt) {
+	for _, stmt := range list {
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok || ifStmt.Else == nil || ifStmt.Init != nil || len(ifStmt.Body.List) == 0 {
+			continue
+		}
+		last := ifStmt.Body.List[len(ifStmt.Body.List)-1]
+		if !util.IsInterruptedStmt(pass, last) {
+			continue
+		}
+		diag := analysis.Diagnostic{
+			Pos:     ifStmt.Else.Pos(),
+			End:     ifStmt.Else.End(),
+			Message: "if block ends with a " + terminator(last) + " statement, so drop this else and outdent its block",
+		}
+		if edit, ok := outdentElse(pass, ifStmt); ok {
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   "Drop the else and outdent its block",
+				TextEdits: []analysis.TextEdit{edit},
+			}}
+		}
+		pass.Report(diag)
+	}
+}
+
+func terminator(stmt ast.Stmt) string {
+	switch x := stmt.(type) {
+	case *ast.ReturnStmt:
+		return "return"
+	case *ast.BranchStmt:
+		return x.Tok.String()
+	}
+	return "terminating call"
+}
+
+// outdentElse returns the edit which drops the else of ifStmt and outdents
+// its block. It returns false if names declared in the else block conflict
+// with names of the enclosing scopes.
+func outdentElse(pass *analysis.Pass, ifStmt *ast.IfStmt) (analysis.TextEdit, bool) {
+	tokFile := pass.Fset.File(ifStmt.Pos())
+	src, err := pass.ReadFile(tokFile.Name())
+	if err != nil {
+		return analysis.TextEdit{}, false
+	}
+	lineStart := tokFile.Offset(tokFile.LineStart(tokFile.Line(ifStmt.Pos())))
+	indent := src[lineStart:tokFile.Offset(ifStmt.Pos())]
+	if len(strings.TrimLeft(string(indent), " \t")) != 0 {
+		return analysis.TextEdit{}, false // the if statement doesn't start its line
+	}
+
+	var text string
+	switch x := ifStmt.Else.(type) {
+	case *ast.IfStmt:
+		text = "\n" + string(indent)
+		return analysis.TextEdit{Pos: ifStmt.Body.End(), End: x.Pos(), NewText: []byte(text)}, true
+	case *ast.BlockStmt:
+		if conflicts(pass, x) {
+			return analysis.TextEdit{}, false
+		}
+		content := string(src[tokFile.Offset(x.Lbrace)+1 : tokFile.Offset(x.Rbrace)])
+		lines := strings.Split(content, "\n")
+		inLiteral := literalLines(tokFile, x)
+		for i, line := range lines {
+			if !inLiteral[tokFile.Line(x.Lbrace)+i] {
+				lines[i] = strings.TrimPrefix(line, "\t")
+			}
+		}
+		text = strings.TrimRight(strings.Join(lines, "\n"), " \t\n")
+		if !strings.HasPrefix(text, "\n") {
+			text = "\n" + string(indent) + strings.TrimSpace(text)
+		}
+		return analysis.TextEdit{Pos: ifStmt.Body.End(), End: x.End(), NewText: []byte(text)}, true
+	}
+	return analysis.TextEdit{}, false
+}
+
+// conflicts reports whether names declared in the else block would conflict
+// with, or shadow, names of the enclosing scopes after outdenting.
+func conflicts(pass *analysis.Pass, block *ast.BlockStmt) bool {
+	scope := pass.TypesInfo.Scopes[block]
+	if scope == nil || scope.Parent() == nil || scope.Parent().Parent() == nil {
+		return true
+	}
+	outer := scope.Parent().Parent() // scope of the if statement, then the enclosing block
+	for _, name := range scope.Names() {
+		if _, obj := outer.LookupParent(name, token.NoPos); obj != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// literalLines returns lines of tokFile which start inside literals of block,
+// e.g. lines of a multi-line raw string after its first line.
+func literalLines(tokFile *token.File, block *ast.BlockStmt) map[int]bool {
+	var lines = make(map[int]bool)
+	ast.Inspect(block, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.BasicLit); ok {
+			for line := tokFile.Line(lit.Pos()) + 1; line <= tokFile.Line(lit.End()); line++ {
+				lines[line] = true
+			}
+		}
+		return true
+	})
+	return lines
+}
+
+// checkNesting reports the outermost if statement of a happy path at the end
+// of body nested at least flags.depth levels, which could be flattened by
+// inverting conditions and an early return or continue.
+func checkNesting(pass *analysis.Pass, body *ast.BlockStmt, early string) {
+	if body == nil || len(body.List) == 0 {
+		return
+	}
+	outermost, ok := body.List[len(body.List)-1].(*ast.IfStmt)
+	if !ok {
+		return
+	}
+	var depth int
+	for ifStmt := outermost; ifStmt != nil && ifStmt.Else == nil; depth++ {
+		list := ifStmt.Body.List
+		ifStmt = nil
+		if len(list) > 0 {
+			ifStmt, _ = list[len(list)-1].(*ast.IfStmt)
+		}
+	}
+	if depth >= flags.depth && flags.depth > 0 {
+		pass.Reportf(outermost.Pos(), "happy path is nested %d levels deep, invert the conditions and %s early", depth, early)
+	}
+}
@@ -0,0 +1,130 @@
+package enum
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/gopherd/tools/cmd/gopherlint/modifier"
+)
+
+const directive = "enum"
+
+const Doc = `check for switch statements which do not cover all constants of @mod:enum types.
+
+A type annotated by
+
+	//@mod:enum
+
+is an enum whose members are the package-level constants of the type. Every
+switch statement over a value of the type must either have a case for each
+member or have a default clause.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:      "enum",
+	Doc:       Doc,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer, modifier.Analyzer},
+	FactTypes: []analysis.Fact{new(enumFact)},
+	Run:       run,
+}
+
+type enumMember struct {
+	Name  string
+	Value string // exact string of constant value
+}
+
+// enumFact is exported for types annotated by @mod:enum.
+type enumFact struct {
+	Members []enumMember
+}
+
+func (enumFact) AFact()         {}
+func (enumFact) String() string { return "enumFact" }
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	modifiers := pass.ResultOf[modifier.Analyzer].(*modifier.Result)
+
+	exportEnums(pass, modifiers)
+
+	inspect.Preorder([]ast.Node{
+		(*ast.SwitchStmt)(nil),
+	}, func(n ast.Node) {
+		x := n.(*ast.SwitchStmt)
+		if x.Tag == nil {
+			return
+		}
+		named, ok := pass.TypesInfo.TypeOf(x.Tag).(*types.Named)
+		if !ok {
+			return
+		}
+		var fact enumFact
+		if !pass.ImportObjectFact(named.Origin().Obj(), &fact) {
+			return
+		}
+		var covered = make(map[string]bool)
+		for _, stmt := range x.Body.List {
+			clause := stmt.(*ast.CaseClause)
+			if clause.List == nil {
+				return // default clause
+			}
+			for _, expr := range clause.List {
+				if value := pass.TypesInfo.Types[expr].Value; value != nil {
+					covered[value.ExactString()] = true
+				}
+			}
+		}
+		var missing []string
+		for _, member := range fact.Members {
+			if !covered[member.Value] {
+				covered[member.Value] = true // report constants with same value once
+				missing = append(missing, member.Name)
+			}
+		}
+		if len(missing) > 0 {
+			pass.Reportf(
+				x.Pos(),
+				"missing cases in switch of enum type %s: %s",
+				types.TypeString(named, types.RelativeTo(pass.Pkg)), strings.Join(missing, ", "),
+			)
+		}
+	})
+	return nil, nil
+}
+
+// exportEnums exports enumFact for enum types declared in the package.
+func exportEnums(pass *analysis.Pass, modifiers *modifier.Result) {
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if _, ok := modifiers.Find(typeName, directive); !ok {
+			continue
+		}
+		var consts []*types.Const
+		for _, name := range scope.Names() {
+			c, ok := scope.Lookup(name).(*types.Const)
+			if ok && types.Identical(c.Type(), typeName.Type()) {
+				consts = append(consts, c)
+			}
+		}
+		sort.Slice(consts, func(i, j int) bool {
+			return consts[i].Pos() < consts[j].Pos()
+		})
+		var fact = new(enumFact)
+		for _, c := range consts {
+			fact.Members = append(fact.Members, enumMember{
+				Name:  c.Name(),
+				Value: c.Val().ExactString(),
+			})
+		}
+		pass.ExportObjectFact(typeName, fact)
+	}
+}
@@ -0,0 +1,18 @@
+package enum_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/gopherd/tools/cmd/gopherlint/enum"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata, err := filepath.Abs("../testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, enum.Analyzer, "enum/...")
+}
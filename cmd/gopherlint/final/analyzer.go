@@ -4,16 +4,17 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
-	"strings"
+	"reflect"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
 
+	"github.com/gopherd/tools/cmd/gopherlint/modifier"
 	"github.com/gopherd/tools/cmd/gopherlint/util"
 )
 
-const directive = "@mod:final"
+const directive = "final"
 
 var flags struct {
 	verbose int
@@ -24,15 +25,31 @@ func init() {
 }
 
 var Analyzer = &analysis.Analyzer{
-	Name:      "final",
-	Doc:       `check for final variables that reassigned or referenced.`,
-	Requires:  []*analysis.Analyzer{inspect.Analyzer},
-	FactTypes: []analysis.Fact{new(finalDeclFact)},
-	Run:       run,
+	Name:       "final",
+	Doc:        `check for final variables that reassigned or referenced.`,
+	Requires:   []*analysis.Analyzer{inspect.Analyzer, modifier.Analyzer},
+	FactTypes:  []analysis.Fact{new(finalDeclFact)},
+	ResultType: reflect.TypeOf((*Result)(nil)),
+	Run:        run,
+}
+
+// Result holds final variables declared in the analyzed package, including
+// local variables whose facts are not exported.
+type Result struct {
+	finals map[types.Object]*finalDeclFact
+}
+
+// Lookup returns the position of the @mod:final directive of obj.
+func (r *Result) Lookup(obj types.Object) (token.Position, bool) {
+	if fact, ok := r.finals[obj]; ok {
+		return fact.Position, true
+	}
+	return token.Position{}, false
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	modifiers := pass.ResultOf[modifier.Analyzer].(*modifier.Result)
 	localFinals := make(map[types.Object]*finalDeclFact)
 
 	inspect.Preorder([]ast.Node{
@@ -43,24 +60,15 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			if x.Tok != token.VAR {
 				return
 			}
-			var groupFinalPos = getFinalDirectivePos(pass, x.Doc)
-			var groupFinalPosition token.Position
-			if groupFinalPos.IsValid() {
-				groupFinalPosition = getFileAndLine(pass, groupFinalPos)
-			}
 			for _, spec := range x.Specs {
 				valueSpec, ok := spec.(*ast.ValueSpec)
 				if !ok {
 					continue
 				}
-				var finalPos = groupFinalPos
-				var position = groupFinalPosition
-				if !finalPos.IsValid() {
-					finalPos = getFinalDirectivePos(pass, valueSpec.Doc)
-					position = getFileAndLine(pass, finalPos)
-				}
-				if finalPos.IsValid() {
-					exportFinalObjects(pass, localFinals, valueSpec.Names, position)
+				for _, name := range valueSpec.Names {
+					if m, ok := modifiers.Find(pass.TypesInfo.Defs[name], directive); ok {
+						exportFinalObjects(pass, localFinals, []*ast.Ident{name}, m.Position)
+					}
 				}
 			}
 		}
@@ -104,7 +112,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			checkFinalObject(pass, localFinals, selector.X, token.AND, true)
 		}
 	})
-	return nil, nil
+	return &Result{finals: localFinals}, nil
 }
 
 func getFileAndLine(pass *analysis.Pass, pos token.Pos) token.Position {
@@ -113,69 +121,57 @@ func getFileAndLine(pass *analysis.Pass, pos token.Pos) token.Position {
 	return position
 }
 
+// checkFinalObject reports expr if it's a final variable or a part of a
+// final variable: a field or an array element. Values referenced through
+// pointers, slices or maps are not parts of the variable.
 func checkFinalObject(pass *analysis.Pass, finals map[types.Object]*finalDeclFact, expr ast.Expr, op token.Token, ignorePointer bool) {
 	expr = util.Unparen(expr)
 	var pos = expr.Pos()
+	if typ := pass.TypesInfo.TypeOf(expr); ignorePointer && typ != nil && util.IsPointer(typ) {
+		return // a method called by a pointer doesn't reference the pointer
+	}
 	var ident *ast.Ident
 	var position token.Position
-	var ok bool
 	var field bool
-	var isPointer bool
-	var typ types.Type
 
-	if x, ok := expr.(*ast.SelectorExpr); ok {
-		selection := pass.TypesInfo.Selections[x]
-		if selection != nil {
-			typ = selection.Obj().Type()
-		}
-	} else if typ == nil {
-		return
-	}
-
-	for expr != nil {
-		expr = util.Unparen(expr)
+	for {
 		switch x := expr.(type) {
 		case *ast.Ident:
 			ident = x
-			expr = nil
 		case *ast.SelectorExpr:
-			expr = x.X
 			ident = x.Sel
-			selection := pass.TypesInfo.Selections[x]
-			if selection != nil {
-				if t := selection.Obj().Type(); t == nil {
-					return
-				} else if util.IsPointer(typ) {
-				}
-			}
 		case *ast.IndexExpr:
-			expr = x.X
-			if typ := pass.TypesInfo.TypeOf(expr); typ != nil {
-				if _, isArray := typ.(*types.Array); !isArray {
-					return
-				}
+			typ := pass.TypesInfo.TypeOf(x.X)
+			if typ == nil {
+				return
 			}
+			if _, isArray := typ.Underlying().(*types.Array); !isArray {
+				return
+			}
+			expr = util.Unparen(x.X)
 			continue
-		}
-		if ident == nil {
-			return
+		default:
+			return // e.g. a call or dereference, not a variable
 		}
 		obj := pass.TypesInfo.ObjectOf(ident)
 		if obj == nil {
 			return
 		}
-		if isPointer && (field || ignorePointer) {
+		var ok bool
+		if position, ok = lookupFinalObject(pass, finals, obj); ok {
+			break
+		}
+		x, ok := expr.(*ast.SelectorExpr)
+		if !ok {
 			return
 		}
-		position, ok = lookupFinalObject(pass, finals, obj)
-		if ok {
-			break
+		selection := pass.TypesInfo.Selections[x]
+		if selection == nil || selection.Kind() != types.FieldVal || selection.Indirect() {
+			return // a qualified identifier, or a field referenced through a pointer
 		}
+		expr = util.Unparen(x.X)
 		field = true
 	}
-	if !ok {
-		return
-	}
 	var prefix string
 	if field {
 		prefix = "field of "
@@ -186,7 +182,7 @@ func checkFinalObject(pass *analysis.Pass, finals map[types.Object]*finalDeclFac
 			pass.Reportf(
 				pos,
 				"cannot assign a value to %sfinal variable %s (directive %q declared here %s)",
-				prefix, ident.Name, directive, position.String(),
+				prefix, ident.Name, "@mod:"+directive, position.String(),
 			)
 		} else {
 			pass.Reportf(
@@ -200,7 +196,7 @@ func checkFinalObject(pass *analysis.Pass, finals map[types.Object]*finalDeclFac
 			pass.Reportf(
 				pos,
 				"cannot reference %sfinal variable %s (directive %q declared here %s)",
-				prefix, ident.Name, directive, position.String(),
+				prefix, ident.Name, "@mod:"+directive, position.String(),
 			)
 		} else {
 			pass.Reportf(
@@ -222,9 +218,11 @@ func exportFinalObjects(pass *analysis.Pass, finals map[types.Object]*finalDeclF
 		if obj == nil {
 			continue
 		}
-		fact := &finalDeclFact{position: position}
+		fact := &finalDeclFact{Position: position}
 		finals[obj] = fact
-		pass.ExportObjectFact(obj, fact)
+		if obj.Parent() == obj.Pkg().Scope() {
+			pass.ExportObjectFact(obj, fact) // facts of local variables are not visible to other packages
+		}
 	}
 }
 
@@ -233,31 +231,21 @@ func lookupFinalObject(pass *analysis.Pass, finals map[types.Object]*finalDeclFa
 		return
 	}
 	if fact, ok := finals[obj]; ok {
-		return fact.position, true
+		return fact.Position, true
 	}
 	var fact = new(finalDeclFact)
 	ok = pass.ImportObjectFact(obj, fact)
 	if ok {
-		pos = fact.position
+		pos = fact.Position
 	}
 	return
 }
 
+// finalDeclFact is exported for variables annotated by @mod:final. Facts are
+// gob-encoded, so its fields must be exported.
 type finalDeclFact struct {
-	position token.Position
+	Position token.Position // position of the directive
 }
 
 func (finalDeclFact) AFact()         {}
 func (finalDeclFact) String() string { return "finalDeclFact" }
-
-func getFinalDirectivePos(pass *analysis.Pass, doc *ast.CommentGroup) token.Pos {
-	if doc == nil {
-		return token.NoPos
-	}
-	for _, comment := range doc.List {
-		if comment.Text == "//"+directive || strings.HasPrefix(comment.Text, "//"+directive+" ") {
-			return comment.Pos()
-		}
-	}
-	return token.NoPos
-}
@@ -0,0 +1,126 @@
+package finalconst
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/gopherd/tools/cmd/gopherlint/final"
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const Doc = `check for final variables which could be declared as constants.
+
+A variable annotated by
+
+	//@mod:final
+
+whose initializer is a constant expression of a basic type, and which is never
+modified, could simply be declared as a constant. The suggested fix rewrites
+"var" to "const" if all variables of the declaration could be constants, and
+declares the type of variables declared without a type, since such a constant
+would be untyped.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "finalconst",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer, final.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	finals := pass.ResultOf[final.Analyzer].(*final.Result)
+	written := util.WrittenVars(pass, inspect)
+
+	inspect.WithStack([]ast.Node{
+		(*ast.GenDecl)(nil),
+	}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		decl := n.(*ast.GenDecl)
+		if !push || decl.Tok != token.VAR {
+			return true
+		}
+		file := stack[0].(*ast.File)
+		var names []*ast.Ident
+		var edits = []analysis.TextEdit{{
+			Pos:     decl.TokPos,
+			End:     decl.TokPos + token.Pos(len(token.VAR.String())),
+			NewText: []byte(token.CONST.String()),
+		}}
+		var fixable = true
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.ValueSpec)
+			for i, name := range spec.Names {
+				obj := pass.TypesInfo.Defs[name]
+				if _, ok := finals.Lookup(obj); ok && len(spec.Values) == len(spec.Names) &&
+					!written[obj] && util.IsConstant(pass, obj, spec.Values[i]) {
+					names = append(names, name)
+				} else {
+					fixable = false
+				}
+			}
+			if spec.Type != nil || !fixable {
+				continue
+			}
+			// A constant declared without a type is untyped, so the type of
+			// the variables is declared to keep it.
+			typ, ok := specType(pass, file, spec)
+			if !ok {
+				fixable = false
+				continue
+			}
+			edits = append(edits, analysis.TextEdit{
+				Pos:     spec.Names[len(spec.Names)-1].End(),
+				NewText: []byte(" " + typ),
+			})
+		}
+		for _, name := range names {
+			diag := analysis.Diagnostic{
+				Pos:     name.Pos(),
+				End:     name.End(),
+				Message: "final variable " + name.Name + " is never modified, declare it as a constant",
+			}
+			if fixable {
+				diag.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   "Declare as a constant",
+					TextEdits: edits,
+				}}
+			}
+			pass.Report(diag)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// specType returns the type of variables declared by spec without a type.
+// It returns false if the variables have different types, or the type is
+// declared in a package which is not imported by file.
+func specType(pass *analysis.Pass, file *ast.File, spec *ast.ValueSpec) (string, bool) {
+	typ := pass.TypesInfo.TypeOf(spec.Names[0])
+	for _, name := range spec.Names[1:] {
+		if !types.Identical(pass.TypesInfo.TypeOf(name), typ) {
+			return "", false
+		}
+	}
+	if named, ok := typ.(*types.Named); ok {
+		if pkg := named.Obj().Pkg(); pkg != nil && pkg != pass.Pkg && !imports(file, pkg.Path()) {
+			return "", false
+		}
+	}
+	return util.TypeString(pass, typ), true
+}
+
+func imports(file *ast.File, path string) bool {
+	for _, spec := range file.Imports {
+		if spec.Name == nil && strings.Trim(spec.Path.Value, `"`) == path {
+			return true
+		}
+	}
+	return false
+}
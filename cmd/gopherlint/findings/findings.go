@@ -0,0 +1,203 @@
+package findings
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Finding is a diagnostic reported by an analyzer.
+type Finding struct {
+	Analyzer       string
+	Package        string
+	Category       string
+	Filename       string
+	Line           int
+	Column         int
+	Message        string
+	SuggestedFixes []SuggestedFix
+}
+
+// A SuggestedFix is a set of edits which fixes a finding.
+type SuggestedFix struct {
+	Message string
+	Edits   []TextEdit
+}
+
+// A TextEdit replaces bytes [Start, End) of file Filename by New.
+type TextEdit struct {
+	Filename string
+	Start    int
+	End      int
+	New      string
+}
+
+// String returns the finding in the plain text format of multichecker.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", f.Filename, f.Line, f.Column, f.Message)
+}
+
+// Run runs the gopherlint executable itself with flag -json and args, and
+// returns findings sorted by position. If some packages fail to be analyzed,
+// e.g. they don't compile, findings of other packages are returned with the
+// errors of the failed packages.
+func Run(args []string) ([]Finding, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return run(exec.Command(executable, append([]string{"-json"}, args...)...))
+}
+
+func run(cmd *exec.Cmd) ([]Finding, error) {
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err != nil {
+		// multichecker exits with 1 if analysis of some packages fails,
+		// the output still holds results of all packages.
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 || !json.Valid(stdout.Bytes()) {
+			return nil, fmt.Errorf("run analyzers: %w", err)
+		}
+	}
+	list, parseErr := Parse(stdout.Bytes())
+	if parseErr == nil && err != nil {
+		parseErr = fmt.Errorf("run analyzers: %w", err)
+	}
+	return list, parseErr
+}
+
+type jsonDiagnostic struct {
+	Category       string `json:"category"`
+	Posn           string `json:"posn"`
+	Message        string `json:"message"`
+	SuggestedFixes []struct {
+		Message string `json:"message"`
+		Edits   []struct {
+			Filename string `json:"filename"`
+			Start    int    `json:"start"`
+			End      int    `json:"end"`
+			New      string `json:"new"`
+		} `json:"edits"`
+	} `json:"suggested_fixes"`
+}
+
+// Parse parses the JSON output of multichecker. Findings reported more than
+// once, e.g. for a package and its test variant, are returned once. Errors of
+// analyzers are returned with the findings, an analyzer failed because of its
+// prerequisites is not reported again.
+func Parse(data []byte) ([]Finding, error) {
+	var tree map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("parse analyzers output: %w", err)
+	}
+	var findings []Finding
+	var errs []string
+	type key struct {
+		analyzer, filename string
+		line, column       int
+		message            string
+	}
+	var seen = make(map[key]bool)
+	for pkg, results := range tree {
+		for analyzer, result := range results {
+			var failure struct {
+				Error string `json:"error"`
+			}
+			if json.Unmarshal(result, &failure) == nil && failure.Error != "" {
+				switch {
+				case strings.HasPrefix(failure.Error, "failed prerequisites"):
+				case failure.Error == skippedError:
+					errs = append(errs, pkg+": "+failure.Error)
+				default:
+					errs = append(errs, pkg+": "+analyzer+": "+failure.Error)
+				}
+				continue
+			}
+			var diags []jsonDiagnostic
+			if err := json.Unmarshal(result, &diags); err != nil {
+				return nil, fmt.Errorf("parse analyzers output: %w", err)
+			}
+			for _, diag := range diags {
+				f := Finding{
+					Analyzer: analyzer,
+					Package:  strings.Fields(pkg)[0], // strip test variant, e.g. "a [a.test]"
+					Category: diag.Category,
+					Message:  diag.Message,
+				}
+				f.Filename, f.Line, f.Column = splitPosn(diag.Posn)
+				k := key{f.Analyzer, f.Filename, f.Line, f.Column, f.Message}
+				if seen[k] {
+					continue
+				}
+				seen[k] = true
+				for _, fix := range diag.SuggestedFixes {
+					var sf = SuggestedFix{Message: fix.Message}
+					for _, edit := range fix.Edits {
+						sf.Edits = append(sf.Edits, TextEdit(edit))
+					}
+					f.SuggestedFixes = append(f.SuggestedFixes, sf)
+				}
+				findings = append(findings, f)
+			}
+		}
+	}
+	Sort(findings)
+	if len(errs) == 0 {
+		return findings, nil
+	}
+	sort.Strings(errs)
+	return findings, errors.New(strings.Join(slices.Compact(errs), "\n"))
+}
+
+// skippedError is the error of analyzers which are not run on a package
+// with errors, e.g. type errors.
+const skippedError = "analysis skipped due to errors in package"
+
+// Sort sorts findings by position, analyzer and message.
+func Sort(findings []Finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		if a.Analyzer != b.Analyzer {
+			return a.Analyzer < b.Analyzer
+		}
+		return a.Message < b.Message
+	})
+}
+
+// splitPosn splits "file:line:column" into its components.
+func splitPosn(posn string) (filename string, line, column int) {
+	filename = posn
+	if i := strings.LastIndexByte(filename, ':'); i >= 0 {
+		if n, err := strconv.Atoi(filename[i+1:]); err == nil {
+			filename, column = filename[:i], n
+		}
+	}
+	if i := strings.LastIndexByte(filename, ':'); i >= 0 {
+		if n, err := strconv.Atoi(filename[i+1:]); err == nil {
+			filename, line = filename[:i], n
+		}
+	}
+	if line == 0 {
+		line, column = column, 0
+	}
+	return
+}
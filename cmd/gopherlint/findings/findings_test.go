@@ -0,0 +1,177 @@
+package findings
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSplitPosn(t *testing.T) {
+	for _, tt := range []struct {
+		posn         string
+		filename     string
+		line, column int
+	}{
+		{"/a/b.go:12:3", "/a/b.go", 12, 3},
+		{"/a/b.go:12", "/a/b.go", 12, 0},
+		{"/a/b.go", "/a/b.go", 0, 0},
+		{`C:\a\b.go:12:3`, `C:\a\b.go`, 12, 3},
+		{`C:\a\b.go:12`, `C:\a\b.go`, 12, 0},
+		{`C:\a\b.go`, `C:\a\b.go`, 0, 0},
+		{"", "", 0, 0},
+	} {
+		filename, line, column := splitPosn(tt.posn)
+		if filename != tt.filename || line != tt.line || column != tt.column {
+			t.Errorf("splitPosn(%q) = %q, %d, %d, want %q, %d, %d",
+				tt.posn, filename, line, column, tt.filename, tt.line, tt.column)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		data    string
+		want    []Finding
+		wantErr bool
+	}{
+		{
+			name: "sorted",
+			data: `{"a": {"y": [
+				{"posn": "/a/b.go:2:1", "message": "m2"},
+				{"posn": "/a/b.go:1:5", "message": "m1", "suggested_fixes": [{"message": "fix", "edits": [{"filename": "/a/b.go", "start": 1, "end": 2, "new": "x"}]}]}
+			]}}`,
+			want: []Finding{
+				{Analyzer: "y", Package: "a", Filename: "/a/b.go", Line: 1, Column: 5, Message: "m1", SuggestedFixes: []SuggestedFix{{
+					Message: "fix",
+					Edits:   []TextEdit{{Filename: "/a/b.go", Start: 1, End: 2, New: "x"}},
+				}}},
+				{Analyzer: "y", Package: "a", Filename: "/a/b.go", Line: 2, Column: 1, Message: "m2"},
+			},
+		},
+		{
+			name: "duplicates of test variant",
+			data: `{
+				"a": {"y": [{"posn": "/a/b.go:1:1", "message": "m"}]},
+				"a [a.test]": {"y": [{"posn": "/a/b.go:1:1", "message": "m"}, {"posn": "/a/b_test.go:1:1", "message": "m"}]}
+			}`,
+			want: []Finding{
+				{Analyzer: "y", Package: "a", Filename: "/a/b.go", Line: 1, Column: 1, Message: "m"},
+				{Analyzer: "y", Package: "a", Filename: "/a/b_test.go", Line: 1, Column: 1, Message: "m"},
+			},
+		},
+		{
+			name: "windows positions",
+			data: `{"a": {"y": [{"posn": "C:\\a\\b.go:12:3", "message": "m"}]}}`,
+			want: []Finding{
+				{Analyzer: "y", Package: "a", Filename: `C:\a\b.go`, Line: 12, Column: 3, Message: "m"},
+			},
+		},
+		{
+			name: "analyzer error",
+			data: `{"a": {"y": {"error": "failed"}, "z": [{"posn": "/a/b.go:1:1", "message": "m"}]}}`,
+			want: []Finding{
+				{Analyzer: "z", Package: "a", Filename: "/a/b.go", Line: 1, Column: 1, Message: "m"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			data:    `[]`,
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse error %v, want error %t", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// brokenOutput is the output of multichecker when package b fails to compile.
+const brokenOutput = `{
+	"b": {
+		"buildssa": {"error": "analysis skipped due to errors in package"},
+		"modifier": {"error": "analysis skipped due to errors in package"},
+		"final": {"error": "failed prerequisites: modifier@b"}
+	},
+	"a": {"final": [{"posn": "/a/a.go:6:12", "message": "cannot assign a value to final variable Limit"}]}
+}`
+
+// TestHelperProcess is not a test, but a fake analyzer process run by TestRun.
+func TestHelperProcess(t *testing.T) {
+	output, ok := os.LookupEnv("FINDINGS_HELPER_OUTPUT")
+	if !ok {
+		return
+	}
+	code, _ := strconv.Atoi(os.Getenv("FINDINGS_HELPER_EXIT"))
+	fmt.Print(output)
+	os.Exit(code)
+}
+
+func TestRun(t *testing.T) {
+	want := []Finding{
+		{Analyzer: "final", Package: "a", Filename: "/a/a.go", Line: 6, Column: 12, Message: "cannot assign a value to final variable Limit"},
+	}
+	for _, tt := range []struct {
+		name     string
+		output   string
+		exitCode int
+		want     []Finding
+		wantErr  string
+	}{
+		{
+			name:   "success",
+			output: `{"a": {"final": [{"posn": "/a/a.go:6:12", "message": "cannot assign a value to final variable Limit"}]}}`,
+			want:   want,
+		},
+		{
+			name:     "broken package",
+			output:   brokenOutput,
+			exitCode: 1,
+			want:     want,
+			wantErr:  "b: analysis skipped due to errors in package",
+		},
+		{
+			name:     "failure without errors",
+			output:   `{}`,
+			exitCode: 1,
+			wantErr:  "run analyzers: exit status 1",
+		},
+		{
+			name:     "invalid output",
+			output:   "usage",
+			exitCode: 1,
+			wantErr:  "run analyzers: exit status 1",
+		},
+		{
+			name:     "other failure",
+			output:   `{}`,
+			exitCode: 2,
+			wantErr:  "run analyzers: exit status 2",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+			cmd.Env = append(os.Environ(),
+				"FINDINGS_HELPER_OUTPUT="+tt.output,
+				"FINDINGS_HELPER_EXIT="+strconv.Itoa(tt.exitCode),
+			)
+			got, err := run(cmd)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("run error %v, want %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("run = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
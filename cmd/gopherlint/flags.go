@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/gopherd/tools/cmd/gopherlint/report"
+)
+
+// commandFlags holds flags of the gopherlint command itself. They are
+// extracted from the command line before the remaining arguments are parsed
+// by multichecker, and registered to flag.CommandLine for usage only.
+var commandFlags = flag.NewFlagSet("gopherlint", flag.ExitOnError)
+
+var flags struct {
+	config           string
+	includeGenerated bool
+	baseline         string
+	baselineWrite    bool
+	format           string
+	newFrom          string
+}
+
+// postProcessFlags are flags handled by the parent process in post-processing
+// mode, they are not passed to the child process which runs the analyzers.
+var postProcessFlags = map[string]bool{
+	"baseline":       true,
+	"baseline.write": true,
+	"format":         true,
+	"new-from":       true,
+}
+
+func init() {
+	commandFlags.StringVar(&flags.config, "config", "", "path of configuration file, looked up from current directory to root of module if empty, disabled if \"none\"")
+	commandFlags.BoolVar(&flags.includeGenerated, "include-generated", false, "report diagnostics in generated files")
+	commandFlags.StringVar(&flags.baseline, "baseline", "", "path of baseline file, only findings not in the baseline are reported")
+	commandFlags.BoolVar(&flags.baselineWrite, "baseline.write", false, "write current findings to the baseline file instead of reporting them")
+	commandFlags.StringVar(&flags.newFrom, "new-from", "", "only report findings on lines changed by git revision range, e.g. \"main...HEAD\", or by unified diff read from stdin if \"-\"")
+	commandFlags.StringVar(&flags.format, "format", "text", "output format: "+strings.Join(report.Formats, ", ")+", findings in formats other than text are written to stdout")
+}
+
+// parseCommandFlags parses command flags in args and returns remaining arguments.
+func parseCommandFlags(args []string) []string {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			rest = append(rest, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		f := commandFlags.Lookup(name)
+		if f == nil {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if isBoolFlag(f) {
+				value = "true"
+			} else if i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+		}
+		if err := commandFlags.Set(name, value); err != nil {
+			commandFlags.Usage()
+			exit(2, "invalid value %q for flag -%s: %v", value, name, err)
+		}
+	}
+	return rest
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// registerCommandFlags registers command flags to flag.CommandLine to show them in usage.
+func registerCommandFlags() {
+	commandFlags.VisitAll(func(f *flag.Flag) {
+		flag.CommandLine.Var(f.Value, f.Name, f.Usage)
+	})
+}
+
+// childArgs returns arguments of the child process in post-processing mode:
+// command flags which are set and not handled by the parent process, followed by args.
+func childArgs(args []string) []string {
+	var result []string
+	commandFlags.Visit(func(f *flag.Flag) {
+		if !postProcessFlags[f.Name] {
+			result = append(result, "-"+f.Name+"="+f.Value.String())
+		}
+	})
+	return append(result, args...)
+}
@@ -1,10 +1,14 @@
 module github.com/gopherd/tools/cmd/gopherlint
 
-go 1.23
+go 1.23.0
 
-require golang.org/x/tools v0.25.0
+require (
+	github.com/golangci/plugin-module-register v0.1.2
+	golang.org/x/tools v0.32.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
-	golang.org/x/mod v0.21.0 // indirect
-	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/mod v0.24.0 // indirect
+	golang.org/x/sync v0.13.0 // indirect
 )
@@ -0,0 +1,353 @@
+package guardedby
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/gopherd/tools/cmd/gopherlint/modifier"
+)
+
+const directive = "guardedby"
+
+const Doc = `check for accesses to @mod:guardedby fields without holding the mutex.
+
+A struct field annotated by
+
+	//@mod:guardedby mu
+
+can only be accessed while the mutex field mu of the same struct is held. This
+analyzer reports reads of the field which are not dominated by mu.Lock() or
+mu.RLock(), and writes which are not dominated by mu.Lock(), or which may be
+executed after an unlock on some path from the lock. Reads of fields of struct
+values, e.g. through value receivers, are checked too. Functions whose names
+end with "Locked" are assumed to be called with the mutex held, and accesses
+to newly allocated values are ignored.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "guardedby",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{buildssa.Analyzer, modifier.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	ssainput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	modifiers := pass.ResultOf[modifier.Analyzer].(*modifier.Result)
+
+	checkDirectives(pass, modifiers)
+	for _, fn := range ssainput.SrcFuncs {
+		if strings.HasSuffix(fn.Name(), "Locked") {
+			continue
+		}
+		c := &checker{
+			pass:      pass,
+			modifiers: modifiers,
+			index:     make(map[ssa.Instruction]int),
+		}
+		c.checkFunc(fn)
+	}
+	return nil, nil
+}
+
+// checkDirectives reports guarded fields declared in the package whose mutex field is invalid.
+func checkDirectives(pass *analysis.Pass, modifiers *modifier.Result) {
+	for _, obj := range pass.TypesInfo.Defs {
+		typeName, ok := obj.(*types.TypeName)
+		if !ok {
+			continue
+		}
+		st, ok := typeName.Type().Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			m, ok := modifiers.Find(st.Field(i), directive)
+			if !ok {
+				continue
+			}
+			args := m.Args()
+			if len(args) != 1 {
+				pass.Reportf(st.Field(i).Pos(), "%s directive of field %s requires exactly one mutex field", directive, st.Field(i).Name())
+				continue
+			}
+			if mutexField(st, args[0]) < 0 {
+				pass.Reportf(st.Field(i).Pos(), "field %s is guarded by %s which is not a sync.Mutex or sync.RWMutex field", st.Field(i).Name(), args[0])
+			}
+		}
+	}
+}
+
+// mutexField returns index of the mutex field named name in st, or -1.
+func mutexField(st *types.Struct, name string) int {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if field.Name() != name {
+			continue
+		}
+		typ := field.Type()
+		if ptr, ok := typ.(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		if isMutex(typ) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isMutex(typ types.Type) bool {
+	switch typ.String() {
+	case "sync.Mutex", "sync.RWMutex":
+		return true
+	}
+	return false
+}
+
+type lockOp int
+
+const (
+	opLock lockOp = iota
+	opUnlock
+	opRLock
+	opRUnlock
+)
+
+// lockEvent is a call to a method of the mutex field of base.
+type lockEvent struct {
+	instr ssa.Instruction
+	base  ssa.Value
+	field int
+	op    lockOp
+}
+
+type checker struct {
+	pass      *analysis.Pass
+	modifiers *modifier.Result
+	index     map[ssa.Instruction]int
+	events    []lockEvent
+}
+
+func (c *checker) checkFunc(fn *ssa.Function) {
+	for _, b := range fn.Blocks {
+		for i, instr := range b.Instrs {
+			c.index[instr] = i
+			if event, ok := c.lockEvent(instr); ok {
+				c.events = append(c.events, event)
+			}
+		}
+	}
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			switch x := instr.(type) {
+			case *ssa.FieldAddr:
+				c.checkFieldAddr(x)
+			case *ssa.Field:
+				c.checkField(x)
+			}
+		}
+	}
+}
+
+func (c *checker) lockEvent(instr ssa.Instruction) (lockEvent, bool) {
+	call, ok := instr.(*ssa.Call)
+	if !ok {
+		return lockEvent{}, false
+	}
+	callee := call.Common().StaticCallee()
+	if callee == nil || callee.Signature.Recv() == nil || len(call.Common().Args) == 0 {
+		return lockEvent{}, false
+	}
+	recv := callee.Signature.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); !ok || !isMutex(ptr.Elem()) {
+		return lockEvent{}, false
+	}
+	var op lockOp
+	switch callee.Name() {
+	case "Lock":
+		op = opLock
+	case "Unlock":
+		op = opUnlock
+	case "RLock":
+		op = opRLock
+	case "RUnlock":
+		op = opRUnlock
+	default:
+		return lockEvent{}, false
+	}
+	mu := call.Common().Args[0]
+	if load, ok := mu.(*ssa.UnOp); ok {
+		mu = load.X // pointer to mutex
+	}
+	fa, ok := mu.(*ssa.FieldAddr)
+	if !ok {
+		return lockEvent{}, false
+	}
+	return lockEvent{instr: instr, base: fa.X, field: fa.Field, op: op}, true
+}
+
+func (c *checker) checkFieldAddr(fa *ssa.FieldAddr) {
+	if isNew(fa.X) {
+		return
+	}
+	ptr, ok := fa.X.Type().Underlying().(*types.Pointer)
+	if !ok {
+		return
+	}
+	c.checkAccess(fa, fa.X, ptr.Elem(), fa.Field, isWrite(fa))
+}
+
+// checkField checks a read of a field of a struct value, e.g. through a
+// value receiver or a value loaded from a pointer.
+func (c *checker) checkField(f *ssa.Field) {
+	var base = f.X
+	if load, ok := f.X.(*ssa.UnOp); ok && load.Op == token.MUL {
+		base = load.X
+	}
+	if isNew(base) {
+		return
+	}
+	c.checkAccess(f, base, f.X.Type(), f.Field, false)
+}
+
+// checkAccess reports instr if it accesses field of a struct of type typ
+// through base without holding the mutex guarding the field.
+func (c *checker) checkAccess(instr ssa.Instruction, base ssa.Value, typ types.Type, index int, write bool) {
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	field := st.Field(index)
+	m, ok := c.modifiers.Find(field, directive)
+	if !ok || len(m.Args()) != 1 {
+		return
+	}
+	mu := mutexField(st, m.Args()[0])
+	if mu < 0 {
+		return
+	}
+	if c.held(instr, base, mu, opLock, opUnlock) {
+		return
+	}
+	if !write && c.held(instr, base, mu, opRLock, opRUnlock) {
+		return
+	}
+	if write && c.held(instr, base, mu, opRLock, opRUnlock) {
+		c.pass.Reportf(instr.Pos(), "field %s is written while %s is only read-locked", field.Name(), st.Field(mu).Name())
+		return
+	}
+	if write {
+		c.pass.Reportf(instr.Pos(), "field %s is written without holding %s", field.Name(), st.Field(mu).Name())
+	} else {
+		c.pass.Reportf(instr.Pos(), "field %s is read without holding %s", field.Name(), st.Field(mu).Name())
+	}
+}
+
+// isNew reports whether v is a newly allocated value, not a local copy of a
+// parameter which is spilled to the heap or stack.
+func isNew(v ssa.Value) bool {
+	alloc, ok := v.(*ssa.Alloc)
+	if !ok {
+		return false
+	}
+	for _, ref := range *alloc.Referrers() {
+		if store, ok := ref.(*ssa.Store); ok && store.Addr == alloc {
+			if _, ok := store.Val.(*ssa.Parameter); ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// held reports whether a lock event dominates instr and no unlock event
+// happens between them on any path.
+func (c *checker) held(instr ssa.Instruction, base ssa.Value, field int, lock, unlock lockOp) bool {
+	for _, l := range c.events {
+		if l.op != lock || l.field != field || !sameValue(l.base, base) || !c.dominates(l.instr, instr) {
+			continue
+		}
+		released := false
+		for _, u := range c.events {
+			if u.op == unlock && u.field == field && sameValue(u.base, base) &&
+				c.reaches(l.instr, u.instr, l.instr) && c.reaches(u.instr, instr, l.instr) {
+				released = true
+				break
+			}
+		}
+		if !released {
+			return true
+		}
+	}
+	return false
+}
+
+// reaches reports whether y may be executed after x on a path which does
+// not pass through instruction avoid.
+func (c *checker) reaches(x, y, avoid ssa.Instruction) bool {
+	// after reports whether avoid is in block b after the instruction at index i.
+	var after = func(b *ssa.BasicBlock, i int) bool {
+		return avoid.Block() == b && c.index[avoid] > i
+	}
+	if x.Block() == y.Block() && c.index[x] < c.index[y] {
+		return !after(x.Block(), c.index[x]) || c.index[avoid] > c.index[y]
+	}
+	if after(x.Block(), c.index[x]) {
+		return false
+	}
+	var seen = make(map[*ssa.BasicBlock]bool)
+	var queue = append([]*ssa.BasicBlock(nil), x.Block().Succs...)
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		if seen[b] {
+			continue
+		}
+		seen[b] = true
+		if b == y.Block() && (avoid.Block() != b || c.index[avoid] > c.index[y]) {
+			return true
+		}
+		if after(b, -1) {
+			continue // blocked by avoid
+		}
+		queue = append(queue, b.Succs...)
+	}
+	return false
+}
+
+// dominates reports whether instruction x is executed before y on every path to y.
+func (c *checker) dominates(x, y ssa.Instruction) bool {
+	if x.Block() == y.Block() {
+		return c.index[x] < c.index[y]
+	}
+	return x.Block().Dominates(y.Block())
+}
+
+// sameValue reports whether x and y denote the same address.
+func sameValue(x, y ssa.Value) bool {
+	if x == y {
+		return true
+	}
+	switch x := x.(type) {
+	case *ssa.FieldAddr:
+		y, ok := y.(*ssa.FieldAddr)
+		return ok && x.Field == y.Field && sameValue(x.X, y.X)
+	case *ssa.UnOp:
+		y, ok := y.(*ssa.UnOp)
+		return ok && x.Op == y.Op && sameValue(x.X, y.X)
+	}
+	return false
+}
+
+func isWrite(fa *ssa.FieldAddr) bool {
+	for _, ref := range *fa.Referrers() {
+		if store, ok := ref.(*ssa.Store); ok && store.Addr == fa {
+			return true
+		}
+	}
+	return false
+}
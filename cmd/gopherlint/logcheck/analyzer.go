@@ -0,0 +1,272 @@
+package logcheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const Doc = `check for misuses of github.com/gopherd/log.
+
+This analyzer reports
+
+  - duplicate field keys in one chain of field methods, e.g.
+    log.Info().Int("id", 1).String("id", "x");
+  - non-constant field keys;
+  - odd numbers of arguments to variadic key/value helpers, whose variadic
+    parameter is named keysAndValues, keyvals or kvs;
+  - format strings which do not match the number of arguments in
+    Printf-style functions, whose parameters end with (format string, args ...any);
+  - error values passed as strings by err.Error() instead of an error field.
+
+Field methods are methods of types in the log package with parameters
+(key string, value T) which return the receiver type. The log package may be
+controlled using flag -logcheck.pkg.`
+
+var flags struct {
+	pkg string
+}
+
+func init() {
+	Analyzer.Flags.StringVar(&flags.pkg, "pkg", "github.com/gopherd/log", "import path of the log package")
+}
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "logcheck",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// kvParams lists names of variadic key/value parameters.
+var kvParams = map[string]bool{
+	"keysAndValues": true,
+	"keyvals":       true,
+	"kvs":           true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	// chained holds field method calls checked as a part of an outer chain
+	var chained = make(map[*ast.CallExpr]bool)
+
+	inspect.Preorder([]ast.Node{
+		(*ast.CallExpr)(nil),
+	}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, sig := logFunc(pass, call)
+		if fn == nil {
+			return
+		}
+		switch {
+		case isFieldMethod(sig):
+			if !chained[call] {
+				checkChain(pass, call, chained)
+			}
+		case isPrintf(sig):
+			checkPrintf(pass, call, fn, sig)
+		case isKeyValues(sig):
+			checkKeyValues(pass, call, fn)
+		}
+	})
+	return nil, nil
+}
+
+// logFunc returns the function or method of the log package called by call.
+func logFunc(pass *analysis.Pass, call *ast.CallExpr) (*types.Func, *types.Signature) {
+	fn, sig, _ := util.GetFunc(pass, util.Unparen(call.Fun))
+	if fn == nil || sig == nil || fn.Pkg() == nil || fn.Pkg().Path() != flags.pkg {
+		return nil, nil
+	}
+	return fn, sig
+}
+
+// isFieldMethod reports whether sig is a method with parameters
+// (key string, value T) which returns the receiver type.
+func isFieldMethod(sig *types.Signature) bool {
+	if sig.Recv() == nil || sig.Variadic() || sig.Params().Len() != 2 || sig.Results().Len() != 1 {
+		return false
+	}
+	if !isString(sig.Params().At(0).Type()) {
+		return false
+	}
+	return types.Identical(sig.Results().At(0).Type(), sig.Recv().Type())
+}
+
+// isPrintf reports whether parameters of sig end with (format string, args ...any).
+func isPrintf(sig *types.Signature) bool {
+	n := sig.Params().Len()
+	return sig.Variadic() && n >= 2 &&
+		sig.Params().At(n-2).Name() == "format" &&
+		isString(sig.Params().At(n-2).Type())
+}
+
+// isKeyValues reports whether the variadic parameter of sig is a key/value list.
+func isKeyValues(sig *types.Signature) bool {
+	n := sig.Params().Len()
+	return sig.Variadic() && kvParams[sig.Params().At(n-1).Name()]
+}
+
+func isString(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// checkChain checks calls of field methods in the chain ending with call.
+func checkChain(pass *analysis.Pass, call *ast.CallExpr, chained map[*ast.CallExpr]bool) {
+	var calls []*ast.CallExpr
+	for {
+		chained[call] = true
+		calls = append(calls, call)
+		selector, ok := util.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+		inner, ok := util.Unparen(selector.X).(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		if _, sig := logFunc(pass, inner); sig == nil || !isFieldMethod(sig) {
+			break
+		}
+		call = inner
+	}
+	var keys = make(map[string]bool)
+	for i := len(calls) - 1; i >= 0; i-- {
+		call := calls[i]
+		if len(call.Args) != 2 {
+			continue
+		}
+		name := call.Fun.(*ast.SelectorExpr).Sel.Name
+		if key, ok := constantString(pass, call.Args[0]); !ok {
+			pass.Reportf(call.Args[0].Pos(), "non-constant key of log field %s", name)
+		} else if keys[key] {
+			pass.Reportf(call.Args[0].Pos(), "duplicate log field key %q", key)
+		} else {
+			keys[key] = true
+		}
+		checkErrorString(pass, call.Args[1], name)
+	}
+}
+
+// checkKeyValues checks arguments of a key/value helper fn.
+func checkKeyValues(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func) {
+	if call.Ellipsis.IsValid() {
+		return // e.g. log.With(kvs...)
+	}
+	sig := fn.Type().(*types.Signature)
+	args := call.Args[sig.Params().Len()-1:]
+	if len(args)%2 != 0 {
+		pass.Reportf(call.Lparen, "odd number of key/value arguments in call to %s", fn.Name())
+	}
+	var keys = make(map[string]bool)
+	for i := 0; i < len(args); i += 2 {
+		if key, ok := constantString(pass, args[i]); !ok {
+			pass.Reportf(args[i].Pos(), "non-constant key in call to %s", fn.Name())
+		} else if keys[key] {
+			pass.Reportf(args[i].Pos(), "duplicate log field key %q", key)
+		} else {
+			keys[key] = true
+		}
+		if i+1 < len(args) {
+			checkErrorString(pass, args[i+1], fn.Name())
+		}
+	}
+}
+
+// checkPrintf checks the number of arguments of a Printf-style call against its format.
+func checkPrintf(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func, sig *types.Signature) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+	index := sig.Params().Len() - 2
+	if index >= len(call.Args) {
+		return
+	}
+	format, ok := constantString(pass, call.Args[index])
+	if !ok {
+		return
+	}
+	want, ok := countVerbs(format)
+	if !ok {
+		return
+	}
+	if got := len(call.Args) - index - 1; got != want {
+		pass.Reportf(call.Lparen, "%s format %s reads %d args, but call has %d args", fn.Name(), strconv.Quote(format), want, got)
+	}
+}
+
+// checkErrorString reports expr if it's a call of Error() on an error value.
+func checkErrorString(pass *analysis.Pass, expr ast.Expr, name string) {
+	call, ok := util.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return
+	}
+	selector, ok := util.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "Error" {
+		return
+	}
+	if typ := pass.TypesInfo.TypeOf(selector.X); typ != nil && types.Implements(typ, errorType) {
+		pass.Reportf(expr.Pos(), "error value passed as string to %s, pass the error itself", name)
+	}
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func constantString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// countVerbs returns the number of arguments read by format, it returns
+// false if format uses explicit argument indexes.
+func countVerbs(format string) (int, bool) {
+	var n int
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++ // flags
+		}
+		i = skipNumber(format, i, &n) // width
+		if i < len(format) && format[i] == '.' {
+			i = skipNumber(format, i+1, &n) // precision
+		}
+		if i >= len(format) {
+			break
+		}
+		switch format[i] {
+		case '%':
+		case '[':
+			return 0, false
+		default:
+			n++
+		}
+	}
+	return n, true
+}
+
+// skipNumber skips a number or a star which reads an argument at format[i:].
+func skipNumber(format string, i int, n *int) int {
+	if i < len(format) && format[i] == '*' {
+		*n++
+		return i + 1
+	}
+	for i < len(format) && '0' <= format[i] && format[i] <= '9' {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+	"github.com/gopherd/tools/cmd/gopherlint/lsp"
+)
+
+// serveLSP serves the language server protocol on stdin and stdout. Each
+// package is analyzed in a child process like in post-processing mode, with
+// args followed by the directory of the package.
+func serveLSP(args []string) int {
+	err := lsp.Serve(os.Stdin, os.Stdout, func(dir string) ([]findings.Finding, error) {
+		return findings.Run(childArgs(slices.Concat(args, []string{dir})))
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gopherlint: lsp: %v\n", err)
+		return 1
+	}
+	return 0
+}
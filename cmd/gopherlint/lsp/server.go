@@ -0,0 +1,326 @@
+// Package lsp implements a minimal language server which publishes findings
+// of gopherlint analyzers as diagnostics when a file is opened or saved, and
+// offers their suggested fixes as code actions.
+//
+// The server only handles the requests and notifications needed to do that,
+// other requests are answered with error MethodNotFound. Files are analyzed
+// as they are saved on disk, changes of unsaved editor buffers are ignored.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+)
+
+// A RunFunc runs analyzers on the package in directory dir.
+type RunFunc func(dir string) ([]findings.Finding, error)
+
+type server struct {
+	in  *textproto.Reader
+	out *bufio.Writer
+	run RunFunc
+
+	findings  map[string][]findings.Finding // last findings of each file
+	published map[string][]string           // files with published diagnostics of each directory
+	shutdown  bool
+}
+
+// Serve serves the language server protocol on r and w until the client
+// sends notification exit. It returns an error if reading or writing
+// messages fails, or the client exits without requesting shutdown.
+func Serve(r io.Reader, w io.Writer, run RunFunc) error {
+	s := &server{
+		in:        textproto.NewReader(bufio.NewReader(r)),
+		out:       bufio.NewWriter(w),
+		run:       run,
+		findings:  make(map[string][]findings.Finding),
+		published: make(map[string][]string),
+	}
+	for {
+		msg, err := s.read()
+		if err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				if err := s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit without shutdown")
+			}
+			return nil
+		}
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// read reads a message with header Content-Length.
+func (s *server) read() (*message, error) {
+	header, err := s.in.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %w", err)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(s.in.R, data); err != nil {
+		return nil, err
+	}
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (s *server) write(msg *message) error {
+	msg.JSONRPC = "2.0"
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(data))
+	s.out.Write(data)
+	return s.out.Flush()
+}
+
+func (s *server) reply(id json.RawMessage, result any, respErr *responseError) error {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	msg := &message{ID: id, Error: respErr}
+	if respErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		msg.Result = data
+	}
+	return s.write(msg)
+}
+
+func (s *server) notify(method string, params any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return s.write(&message{Method: method, Params: data})
+}
+
+func (s *server) handle(msg *message) error {
+	var result any
+	var respErr *responseError
+	switch msg.Method {
+	case "initialize":
+		result = initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:   textDocumentSyncOptions{OpenClose: true},
+				CodeActionProvider: true,
+			},
+			ServerInfo: serverInfo{Name: "gopherlint"},
+		}
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/didOpen", "textDocument/didSave":
+		var params textDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			respErr = &responseError{Code: codeInvalidParams, Message: err.Error()}
+			break
+		}
+		if filename, ok := uriToFilename(params.TextDocument.URI); ok {
+			if err := s.analyze(filepath.Dir(filename)); err != nil {
+				return err
+			}
+		}
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			respErr = &responseError{Code: codeInvalidParams, Message: err.Error()}
+			break
+		}
+		result = s.codeActions(params)
+	default:
+		respErr = &responseError{Code: codeMethodNotFound, Message: "method not supported: " + msg.Method}
+	}
+	if msg.ID == nil {
+		return nil // a notification, e.g. initialized or textDocument/didClose
+	}
+	return s.reply(msg.ID, result, respErr)
+}
+
+// analyze runs analyzers on the package in dir and publishes diagnostics
+// of its files. Diagnostics of files without findings are cleared.
+func (s *server) analyze(dir string) error {
+	list, err := s.run(dir)
+	if err != nil {
+		if err := s.notify("window/logMessage", logMessageParams{Type: messageError, Message: err.Error()}); err != nil {
+			return err
+		}
+		if list == nil {
+			return nil // keep the diagnostics published, e.g. while the package does not compile
+		}
+	}
+	for _, filename := range s.published[dir] {
+		delete(s.findings, filename)
+	}
+	var files []string
+	for _, f := range list {
+		if filepath.Dir(f.Filename) != dir {
+			continue
+		}
+		if _, ok := s.findings[f.Filename]; !ok {
+			files = append(files, f.Filename)
+		}
+		s.findings[f.Filename] = append(s.findings[f.Filename], f)
+	}
+	for _, filename := range s.published[dir] {
+		if _, ok := s.findings[filename]; !ok {
+			files = append(files, filename) // clear diagnostics
+		}
+	}
+	s.published[dir] = nil
+	for _, filename := range files {
+		var diagnostics = []diagnostic{}
+		content, _ := os.ReadFile(filename)
+		for _, f := range s.findings[filename] {
+			diagnostics = append(diagnostics, toDiagnostic(content, f))
+		}
+		if len(diagnostics) > 0 {
+			s.published[dir] = append(s.published[dir], filename)
+		}
+		if err := s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         filenameToURI(filename),
+			Diagnostics: diagnostics,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// codeActions returns suggested fixes of findings in the range of params.
+func (s *server) codeActions(params codeActionParams) []codeAction {
+	var actions = []codeAction{}
+	filename, ok := uriToFilename(params.TextDocument.URI)
+	if !ok {
+		return actions
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return actions
+	}
+	var contents = map[string][]byte{filename: content}
+	for _, f := range s.findings[filename] {
+		line := f.Line - 1
+		if line < params.Range.Start.Line || line > params.Range.End.Line {
+			continue
+		}
+		for _, fix := range f.SuggestedFixes {
+			action := codeAction{
+				Title:       fix.Message,
+				Kind:        "quickfix",
+				Diagnostics: []diagnostic{toDiagnostic(content, f)},
+				Edit:        workspaceEdit{Changes: make(map[string][]textEdit)},
+			}
+			for _, edit := range fix.Edits {
+				data, ok := contents[edit.Filename]
+				if !ok {
+					data, _ = os.ReadFile(edit.Filename)
+					contents[edit.Filename] = data
+				}
+				uri := filenameToURI(edit.Filename)
+				action.Edit.Changes[uri] = append(action.Edit.Changes[uri], textEdit{
+					Range:   lspRange{Start: positionOf(data, edit.Start), End: positionOf(data, edit.End)},
+					NewText: edit.New,
+				})
+			}
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+func toDiagnostic(content []byte, f findings.Finding) diagnostic {
+	start := offsetOf(content, f.Line, f.Column)
+	end := start
+	for end < len(content) && isWordByte(content[end]) {
+		end++ // highlight the identifier at the position
+	}
+	return diagnostic{
+		Range:    lspRange{Start: positionOf(content, start), End: positionOf(content, end)},
+		Severity: severityWarning,
+		Source:   "gopherlint",
+		Code:     f.Analyzer,
+		Message:  f.Message,
+	}
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || b >= utf8.RuneSelf
+}
+
+// offsetOf returns the byte offset of 1-based line and column in content.
+func offsetOf(content []byte, line, column int) int {
+	var offset int
+	for ; line > 1 && offset < len(content); offset++ {
+		if content[offset] == '\n' {
+			line--
+		}
+	}
+	if column > 0 {
+		offset += column - 1
+	}
+	return min(offset, len(content))
+}
+
+// positionOf returns the position of byte offset in content, its character
+// is counted in UTF-16 code units as required by the protocol.
+func positionOf(content []byte, offset int) position {
+	var pos position
+	offset = min(offset, len(content))
+	for i := 0; i < offset; {
+		r, size := utf8.DecodeRune(content[i:])
+		i += size
+		switch {
+		case r == '\n':
+			pos.Line++
+			pos.Character = 0
+		case r >= 0x10000:
+			pos.Character += 2 // a surrogate pair
+		default:
+			pos.Character++
+		}
+	}
+	return pos
+}
+
+func uriToFilename(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
+
+func filenameToURI(filename string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(filename)}).String()
+}
@@ -0,0 +1,153 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+)
+
+const source = `package a
+
+// ü😀
+var limit = 10
+`
+
+func TestServe(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(filename, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	offset := len("package a\n\n// ü😀\nvar ")
+	finding := findings.Finding{
+		Analyzer: "finalconst",
+		Filename: filename,
+		Line:     4,
+		Column:   5,
+		Message:  "final variable limit is never modified, declare it as a constant",
+		SuggestedFixes: []findings.SuggestedFix{{
+			Message: "Declare as a constant",
+			Edits:   []findings.TextEdit{{Filename: filename, Start: offset - 4, End: offset - 1, New: "const"}},
+		}},
+	}
+	var list = []findings.Finding{finding}
+	run := func(d string) ([]findings.Finding, error) {
+		if d != dir {
+			t.Errorf("run analyzers in %s, want %s", d, dir)
+		}
+		return list, nil
+	}
+
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(serverIn, serverOut, run)
+		serverOut.Close()
+	}()
+	c := &client{t: t, in: textproto.NewReader(bufio.NewReader(clientIn)), out: clientOut}
+	uri := filenameToURI(filename)
+
+	c.send(1, "initialize", map[string]any{})
+	if got := c.receive(); got.Error != nil || !json.Valid(got.Result) {
+		t.Fatalf("initialize: %+v", got)
+	}
+	c.send(nil, "initialized", map[string]any{})
+
+	c.send(nil, "textDocument/didSave", textDocumentParams{TextDocument: textDocumentIdentifier{URI: uri}})
+	var published publishDiagnosticsParams
+	c.decode(c.receive().Params, &published)
+	if len(published.Diagnostics) != 1 {
+		t.Fatalf("published %d diagnostics, want 1", len(published.Diagnostics))
+	}
+	want := lspRange{Start: position{Line: 3, Character: 4}, End: position{Line: 3, Character: 9}}
+	if d := published.Diagnostics[0]; d.Range != want || d.Code != "finalconst" {
+		t.Errorf("diagnostic %+v, want range %+v", d, want)
+	}
+
+	c.send(2, "textDocument/codeAction", codeActionParams{
+		TextDocument: textDocumentIdentifier{URI: uri},
+		Range:        lspRange{Start: position{Line: 3}, End: position{Line: 3}},
+	})
+	var actions []codeAction
+	c.decode(c.receive().Result, &actions)
+	if len(actions) != 1 || len(actions[0].Edit.Changes[uri]) != 1 {
+		t.Fatalf("code actions %+v, want 1 action with 1 edit", actions)
+	}
+	edit := actions[0].Edit.Changes[uri][0]
+	if want := (lspRange{Start: position{Line: 3}, End: position{Line: 3, Character: 3}}); edit.Range != want || edit.NewText != "const" {
+		t.Errorf("edit %+v, want range %+v", edit, want)
+	}
+
+	list = nil
+	c.send(nil, "textDocument/didSave", textDocumentParams{TextDocument: textDocumentIdentifier{URI: uri}})
+	c.decode(c.receive().Params, &published)
+	if published.URI != uri || len(published.Diagnostics) != 0 {
+		t.Errorf("published %+v, want diagnostics of %s cleared", published, uri)
+	}
+
+	c.send(3, "textDocument/hover", map[string]any{})
+	if got := c.receive(); got.Error == nil || got.Error.Code != codeMethodNotFound {
+		t.Errorf("hover: %+v, want error MethodNotFound", got)
+	}
+	c.send(4, "shutdown", nil)
+	if got := c.receive(); string(got.Result) != "null" {
+		t.Errorf("shutdown result %s, want null", got.Result)
+	}
+	c.send(nil, "exit", nil)
+	if err := <-done; err != nil {
+		t.Errorf("Serve: %v", err)
+	}
+}
+
+type client struct {
+	t   *testing.T
+	in  *textproto.Reader
+	out io.Writer
+}
+
+func (c *client) send(id any, method string, params any) {
+	msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+	if id != nil {
+		msg["id"] = id
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.out, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *client) receive() *message {
+	header, err := c.in.ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(c.in.R, data); err != nil {
+		c.t.Fatal(err)
+	}
+	var msg message
+	c.decode(data, &msg)
+	return &msg
+}
+
+func (c *client) decode(data []byte, v any) {
+	if err := json.Unmarshal(data, v); err != nil {
+		c.t.Fatalf("decode %s: %v", data, err)
+	}
+}
@@ -1,15 +1,118 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/multichecker"
+	"golang.org/x/tools/go/analysis/unitchecker"
 
-	"github.com/gopherd/tools/cmd/gopherlint/final"
-	"github.com/gopherd/tools/cmd/gopherlint/unusedresult"
+	"github.com/gopherd/tools/cmd/gopherlint/analyzers"
+	"github.com/gopherd/tools/cmd/gopherlint/config"
+	"github.com/gopherd/tools/cmd/gopherlint/report"
+	"github.com/gopherd/tools/cmd/gopherlint/util"
 )
 
 func main() {
-	multichecker.Main(
-		unusedresult.Analyzer,
-		final.Analyzer,
-	)
+	os.Args = append(os.Args[:1], parseCommandFlags(os.Args[1:])...)
+	registerCommandFlags()
+	if !slices.Contains(report.Formats, flags.format) {
+		exit(2, "unknown format %q, supported formats: %s", flags.format, strings.Join(report.Formats, ", "))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "lsp" {
+		if flags.baseline != "" || flags.newFrom != "" || flags.format != "text" {
+			exit(2, "flags -baseline, -new-from and -format are not supported by gopherlint lsp")
+		}
+		os.Exit(serveLSP(os.Args[2:]))
+	}
+	var vetTool = isVetTool(os.Args[1:])
+	if flags.baseline != "" || flags.newFrom != "" || flags.format != "text" {
+		if vetTool {
+			exit(2, "flags -baseline, -new-from and -format are not supported by go vet -vettool")
+		}
+		os.Exit(postProcess(os.Args[1:]))
+	}
+
+	var enabled = analyzers.All()
+	cfg, err := loadConfig()
+	if err != nil {
+		exit(1, "%v", err)
+	}
+	if cfg != nil {
+		if enabled, err = cfg.Apply(enabled); err != nil {
+			exit(1, "%v", err)
+		}
+	}
+	if !flags.includeGenerated {
+		skipGenerated(enabled, cfg)
+	}
+	if cfg != nil {
+		cfg.Wrap(enabled...)
+	}
+	if vetTool {
+		unitchecker.Main(enabled...)
+	}
+	multichecker.Main(enabled...)
+}
+
+// isVetTool reports whether gopherlint is run by "go vet -vettool", which
+// describes the tool by flag -V=full or -flags, and then analyzes each
+// package described by a JSON configuration file *.cfg.
+func isVetTool(args []string) bool {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-V=") || arg == "-flags" {
+			return true
+		}
+	}
+	return len(args) > 0 && strings.HasSuffix(args[len(args)-1], ".cfg")
+}
+
+// loadConfig loads the configuration file, it returns nil if no configuration file found.
+func loadConfig() (*config.Config, error) {
+	filename := flags.config
+	if filename == "none" {
+		return nil, nil
+	}
+	if filename == "" {
+		var err error
+		if filename, err = config.Find("."); err != nil || filename == "" {
+			return nil, err
+		}
+	}
+	return config.Load(filename)
+}
+
+// skipGenerated wraps analyzers so that their diagnostics in generated files are dropped.
+func skipGenerated(analyzers []*analysis.Analyzer, cfg *config.Config) {
+	for _, a := range analyzers {
+		var runFunc = a.Run
+		a.Run = func(pass *analysis.Pass) (interface{}, error) {
+			var generated = make(map[string]bool)
+			for _, file := range pass.Files {
+				filename := pass.Fset.Position(file.Pos()).Filename
+				if util.IsGeneratedFile(filename, file) || (cfg != nil && cfg.IsGenerated(filename)) {
+					generated[filename] = true
+				}
+			}
+			if len(generated) == 0 {
+				return runFunc(pass)
+			}
+			report := pass.Report
+			pass.Report = func(diag analysis.Diagnostic) {
+				if !generated[pass.Fset.Position(diag.Pos).Filename] {
+					report(diag)
+				}
+			}
+			defer func() { pass.Report = report }()
+			return runFunc(pass)
+		}
+	}
+}
+
+func exit(code int, format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "gopherlint: "+format+"\n", args...)
+	os.Exit(code)
 }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/gopherd/tools/cmd/gopherlint/unusedresult"
+)
+
+func TestSkipGenerated(t *testing.T) {
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unusedresult.Analyzer.Flags.Set("types", "*generated/a.user"); err != nil {
+		t.Fatal(err)
+	}
+	a := *unusedresult.Analyzer
+	skipGenerated([]*analysis.Analyzer{&a}, nil)
+	analysistest.Run(t, testdata, &a, "generated/...")
+}
+
+// vetSource is a package of module vet, which uses directives whose facts
+// are exported to other packages.
+var vetSource = map[string]string{
+	"go.mod": "module vet\n\ngo 1.23\n",
+	"a/a.go": `package a
+
+//@mod:final
+var Limit = 10
+
+//@mod:enum
+type Color int
+
+const (
+	Red Color = iota
+	Green
+)
+
+//@mod:pure
+func Add(x, y int) int { return x + y }
+`,
+	"b/b.go": `package b
+
+import "vet/a"
+
+func F(c a.Color) int {
+	a.Limit = 1
+	switch c {
+	case a.Red:
+	}
+	return a.Add(1, 2)
+}
+`,
+}
+
+func TestVetTool(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go vet in short mode")
+	}
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "gopherlint")
+	build := exec.Command("go", "build", "-o", tool, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	module := filepath.Join(dir, "vet")
+	for name, content := range vetSource {
+		filename := filepath.Join(module, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	vet := exec.Command("go", "vet", "-vettool="+tool, "./...")
+	vet.Dir = module
+	// The exit status is not checked, since it depends on the version of
+	// the go command whether findings reported as JSON fail go vet.
+	out, _ := vet.CombinedOutput()
+	if strings.Contains(string(out), "internal error") {
+		t.Fatalf("go vet failed:\n%s", out)
+	}
+	for _, want := range []string{
+		"cannot assign a value to final variable Limit",
+		"missing cases",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("go vet output does not contain %q:\n%s", want, out)
+		}
+	}
+}
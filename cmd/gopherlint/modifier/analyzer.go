@@ -1,8 +1,26 @@
+// Package modifier collects //@mod: directives from document comments and
+// exports them as facts of the declared objects.
+//
+// Directives are declared in the following scopes, from the most to the least
+// specific one:
+//
+//   - spec: document comment of a value, type, function, field, parameter or import.
+//   - group: document comment of a const, var or type declaration group.
+//   - file: package clause comment, applied to every top-level declaration of the file.
+//   - package: package clause comment with prefix "package ", e.g. "//@mod:package final",
+//     applied to every top-level declaration of the package.
+//
+// A directive inherited from an enclosing scope is overridden by a directive
+// with the same name in a more specific scope, and removed by a negated
+// directive, e.g. "//@mod:!final".
 package modifier
 
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -10,69 +28,155 @@ import (
 	"golang.org/x/tools/go/ast/inspector"
 )
 
-const modifierDirective = "//@mod:"
+const (
+	modifierDirective = "//@mod:"
+	packageScope      = "package "
+	negation          = "!"
+)
 
 type Modifier struct {
 	Directive string         // prefix "@mod:" has been removed
 	Position  token.Position // position of directive
 }
 
+// Name returns the name of directive, e.g. "internal" for "//@mod:internal a/...".
+func (m Modifier) Name() string {
+	name, _, _ := strings.Cut(m.Directive, " ")
+	return name
+}
+
+// Args returns the space-separated arguments of directive.
+func (m Modifier) Args() []string {
+	_, args, _ := strings.Cut(m.Directive, " ")
+	return strings.Fields(args)
+}
+
 type ModifierFact struct {
 	Modifiers []Modifier
 }
 
-func (ModifierFact) AFact()         {}
-func (ModifierFact) String() string { return "ModifierFact" }
+func (ModifierFact) AFact() {}
+
+func (f ModifierFact) String() string {
+	var directives = make([]string, 0, len(f.Modifiers))
+	for _, m := range f.Modifiers {
+		directives = append(directives, m.Directive)
+	}
+	return "ModifierFact: " + strings.Join(directives, ", ")
+}
+
+// Result holds modifiers of objects declared in the analyzed package and
+// modifiers imported from its dependencies.
+type Result struct {
+	objects map[types.Object][]Modifier
+}
+
+// Lookup returns modifiers of obj.
+func (r *Result) Lookup(obj types.Object) []Modifier {
+	if obj == nil {
+		return nil
+	}
+	return r.objects[origin(obj)]
+}
+
+// Find returns the first modifier of obj named name.
+func (r *Result) Find(obj types.Object, name string) (Modifier, bool) {
+	for _, m := range r.Lookup(obj) {
+		if m.Name() == name {
+			return m, true
+		}
+	}
+	return Modifier{}, false
+}
+
+// origin returns the generic object of obj if obj is an instantiated one.
+func origin(obj types.Object) types.Object {
+	switch x := obj.(type) {
+	case *types.Func:
+		return x.Origin()
+	case *types.Var:
+		return x.Origin()
+	}
+	return obj
+}
 
 var Analyzer = &analysis.Analyzer{
-	Name:      "modifier",
-	Doc:       `lookup modifiers`,
-	Requires:  []*analysis.Analyzer{inspect.Analyzer},
-	FactTypes: []analysis.Fact{new(ModifierFact)},
-	Run:       run,
+	Name:       "modifier",
+	Doc:        `lookup modifiers`,
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes:  []analysis.Fact{new(ModifierFact)},
+	ResultType: reflect.TypeOf((*Result)(nil)),
+	Run:        run,
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
+	var pkgModifiers []Modifier
+	for _, file := range pass.Files {
+		_, modifiers := lookupFileModifiers(pass, file.Doc)
+		pkgModifiers = mergeModifiers(pkgModifiers, modifiers)
+	}
+
+	// defaults holds modifiers inherited by top-level declarations of the current file
+	var defaults []Modifier
+	var file *ast.File
+	var topLevel = make(map[ast.Decl]bool)
+
 	inspect.Preorder([]ast.Node{
 		(*ast.File)(nil),
 		(*ast.Field)(nil),
 		(*ast.ImportSpec)(nil),
-		(*ast.ValueSpec)(nil),
-		(*ast.TypeSpec)(nil),
 		(*ast.GenDecl)(nil),
 		(*ast.FuncDecl)(nil),
 	}, func(n ast.Node) {
 		switch x := n.(type) {
 		case *ast.File:
-			lookupAndExportModifiers(pass, x.Doc, x.Name)
+			file = x
+			fileModifiers, _ := lookupFileModifiers(pass, x.Doc)
+			defaults = mergeModifiers(fileModifiers, pkgModifiers)
+			for _, decl := range x.Decls {
+				topLevel[decl] = true
+			}
 		case *ast.Field:
 			lookupAndExportModifiers(pass, x.Doc, x.Names...)
 		case *ast.ImportSpec:
 			lookupAndExportModifiers(pass, x.Doc, x.Name)
-		case *ast.ValueSpec:
-			lookupAndExportModifiers(pass, x.Doc, x.Names...)
-		case *ast.TypeSpec:
-			lookupAndExportModifiers(pass, x.Doc, x.Name)
 		case *ast.GenDecl:
+			if x.Tok == token.IMPORT {
+				return
+			}
 			var modifiers = lookupModifiers(pass, x.Doc)
+			if topLevel[x] {
+				modifiers = mergeModifiers(modifiers, defaults)
+			}
 			for _, spec := range x.Specs {
-				valueSpec, ok := spec.(*ast.ValueSpec)
-				if !ok {
-					continue
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					exportModifiers(
+						pass,
+						mergeModifiers(lookupModifiers(pass, spec.Doc), modifiers),
+						spec.Names...,
+					)
+				case *ast.TypeSpec:
+					exportModifiers(
+						pass,
+						mergeModifiers(lookupModifiers(pass, spec.Doc), modifiers),
+						spec.Name,
+					)
 				}
-				exportModifiers(
-					pass,
-					mergeModifiers(lookupModifiers(pass, valueSpec.Doc), modifiers),
-					valueSpec.Names...,
-				)
 			}
 		case *ast.FuncDecl:
-			lookupAndExportModifiers(pass, x.Doc, x.Name)
+			exportModifiers(pass, mergeModifiers(lookupModifiers(pass, x.Doc), defaults), x.Name)
+			lookupAndExportParamModifiers(pass, file, x.Type.Params)
 		}
 	})
-	return nil, nil
+
+	result := &Result{objects: make(map[types.Object][]Modifier)}
+	for _, fact := range pass.AllObjectFacts() {
+		result.objects[fact.Object] = fact.Fact.(*ModifierFact).Modifiers
+	}
+	return result, nil
 }
 
 func getPosition(pass *analysis.Pass, pos token.Pos) token.Position {
@@ -97,8 +201,42 @@ func lookupModifiers(pass *analysis.Pass, doc *ast.CommentGroup) []Modifier {
 	return modifiers
 }
 
+// lookupFileModifiers returns modifiers declared in the package clause
+// comment of a file: modifiers applied to the file and modifiers with
+// prefix "package " applied to the package.
+func lookupFileModifiers(pass *analysis.Pass, doc *ast.CommentGroup) (file, pkg []Modifier) {
+	for _, m := range lookupModifiers(pass, doc) {
+		if directive, ok := strings.CutPrefix(m.Directive, packageScope); ok {
+			m.Directive = strings.TrimSpace(directive)
+			pkg = append(pkg, m)
+		} else {
+			file = append(file, m)
+		}
+	}
+	return
+}
+
+// mergeModifiers merges modifiers of an enclosing scope src into modifiers dst.
+// Modifiers of dst take precedence over modifiers of src with the same name,
+// and a negated modifier "!name" of dst removes modifiers named name of src.
+// Negated modifiers are not kept in the result.
 func mergeModifiers(dst, src []Modifier) []Modifier {
-	return append(dst, src...)
+	var result []Modifier
+	var names = make(map[string]bool)
+	for _, m := range dst {
+		if name, ok := strings.CutPrefix(m.Name(), negation); ok {
+			names[name] = true
+			continue
+		}
+		names[m.Name()] = true
+		result = append(result, m)
+	}
+	for _, m := range src {
+		if !names[m.Name()] {
+			result = append(result, m)
+		}
+	}
+	return result
 }
 
 func exportModifiers(pass *analysis.Pass, modifiers []Modifier, names ...*ast.Ident) {
@@ -106,7 +244,7 @@ func exportModifiers(pass *analysis.Pass, modifiers []Modifier, names ...*ast.Id
 		return
 	}
 	for _, v := range names {
-		if v.Name == "_" || v.Name == "" {
+		if v == nil || v.Name == "_" || v.Name == "" {
 			continue
 		}
 		obj := pass.TypesInfo.ObjectOf(v)
@@ -116,15 +254,64 @@ func exportModifiers(pass *analysis.Pass, modifiers []Modifier, names ...*ast.Id
 		fact := &ModifierFact{
 			Modifiers: modifiers,
 		}
-		println("export modifier", len(modifiers))
 		pass.ExportObjectFact(obj, fact)
 	}
 }
 
 func lookupAndExportModifiers(pass *analysis.Pass, doc *ast.CommentGroup, names ...*ast.Ident) {
-	modifiers := lookupModifiers(pass, doc)
+	modifiers := mergeModifiers(lookupModifiers(pass, doc), nil)
 	if len(modifiers) == 0 {
 		return
 	}
 	exportModifiers(pass, modifiers, names...)
 }
+
+// Scan returns modifiers declared in all comments of file, including
+// comments which are not document comments of any declaration. The prefix
+// "package " of package scope modifiers is removed.
+func Scan(pass *analysis.Pass, file *ast.File) []Modifier {
+	var modifiers []Modifier
+	for _, group := range file.Comments {
+		if group == file.Doc {
+			fileModifiers, pkgModifiers := lookupFileModifiers(pass, group)
+			modifiers = append(modifiers, fileModifiers...)
+			modifiers = append(modifiers, pkgModifiers...)
+			continue
+		}
+		modifiers = append(modifiers, lookupModifiers(pass, group)...)
+	}
+	return modifiers
+}
+
+// lookupAndExportParamModifiers exports modifiers of parameters. Fields of
+// parameter lists have no document comments in go/ast, so modifiers of a
+// parameter are looked up in comments between the previous line of code in
+// the list and the parameter:
+//
+//	func f(
+//		//@mod:noescape
+//		p *T,
+//	)
+func lookupAndExportParamModifiers(pass *analysis.Pass, file *ast.File, params *ast.FieldList) {
+	if file == nil || params == nil {
+		return
+	}
+	var prev = params.Opening
+	for _, field := range params.List {
+		i := sort.Search(len(file.Comments), func(i int) bool {
+			return file.Comments[i].Pos() > prev
+		})
+		var doc ast.CommentGroup
+		for ; i < len(file.Comments) && file.Comments[i].End() < field.Pos(); i++ {
+			group := file.Comments[i]
+			if prev != params.Opening && pass.Fset.Position(group.Pos()).Line == pass.Fset.Position(prev).Line {
+				continue // a trailing comment of the previous parameter
+			}
+			doc.List = append(doc.List, group.List...)
+		}
+		if len(doc.List) > 0 {
+			lookupAndExportModifiers(pass, &doc, field.Names...)
+		}
+		prev = field.End()
+	}
+}
@@ -0,0 +1,116 @@
+package nilnil
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/gopherd/tools/cmd/gopherlint/modifier"
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const directive = "nilnil"
+
+const Doc = `check for functions returning a nil pointer-like value together with a nil error.
+
+A function whose results are (T, error), where T is a pointer, interface, map,
+channel or function type, should not return nil for both results, since
+callers usually use the value without checking it after the error is nil.
+Both literal nils and variables known to be nil are reported. A function
+which intentionally returns nil, nil, e.g. for "not found", is annotated by
+
+	//@mod:nilnil
+
+or listed in flag -nilnil.allow, closures inside it are allowed too.`
+
+var allowFuncs util.StringSetFlag
+
+func init() {
+	Analyzer.Flags.Var(&allowFuncs, "allow",
+		"comma-separated list of functions allowed to return nil, nil, e.g. pkg/path.Func or (*pkg/path.Type).Method")
+}
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "nilnil",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{buildssa.Analyzer, modifier.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	ssainput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	modifiers := pass.ResultOf[modifier.Analyzer].(*modifier.Result)
+
+	for _, fn := range ssainput.SrcFuncs {
+		results := fn.Signature.Results()
+		if results.Len() != 2 || !isPointerLike(results.At(0).Type()) || !util.IsError(results.At(1).Type()) {
+			continue
+		}
+		if allowed(modifiers, fn) {
+			continue
+		}
+		for _, b := range fn.Blocks {
+			ret, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return)
+			if !ok || !ret.Pos().IsValid() {
+				continue
+			}
+			if isNil(ret.Results[0], nil) && isNil(ret.Results[1], nil) {
+				pass.Reportf(ret.Pos(), "return of nil %s with nil error", types.TypeString(results.At(0).Type(), types.RelativeTo(pass.Pkg)))
+			}
+		}
+	}
+	return nil, nil
+}
+
+func isPointerLike(typ types.Type) bool {
+	if _, ok := typ.(*types.TypeParam); ok {
+		return false
+	}
+	return util.IsGenericPointer(typ.Underlying())
+}
+
+// allowed reports whether fn, or the declared function enclosing it, is
+// allowed to return nil, nil.
+func allowed(modifiers *modifier.Result, fn *ssa.Function) bool {
+	for fn.Parent() != nil {
+		fn = fn.Parent()
+	}
+	obj, ok := fn.Object().(*types.Func)
+	if !ok {
+		return false
+	}
+	if _, ok := modifiers.Find(obj, directive); ok {
+		return true
+	}
+	return allowFuncs[util.GetFuncNameBySign(obj, obj.Type().(*types.Signature))]
+}
+
+// isNil reports whether v is always nil: a nil constant, or a phi node
+// whose edges are all nil.
+func isNil(v ssa.Value, visited map[*ssa.Phi]bool) bool {
+	switch v := v.(type) {
+	case *ssa.Const:
+		return v.IsNil()
+	case *ssa.MakeInterface:
+		return false // a non-nil interface even if the value is nil
+	case *ssa.ChangeType:
+		return isNil(v.X, visited)
+	case *ssa.Phi:
+		if visited[v] {
+			return true // cycle, other edges decide
+		}
+		if visited == nil {
+			visited = make(map[*ssa.Phi]bool)
+		}
+		visited[v] = true
+		for _, edge := range v.Edges {
+			if !isNil(edge, visited) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package nilnil_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/gopherd/tools/cmd/gopherlint/nilnil"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata, err := filepath.Abs("../testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := nilnil.Analyzer.Flags.Set("allow", "nilnil/a.findAllowed"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, nilnil.Analyzer, "nilnil/...")
+}
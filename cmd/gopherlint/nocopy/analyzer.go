@@ -0,0 +1,129 @@
+package nocopy
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/gopherd/tools/cmd/gopherlint/modifier"
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const directive = "nocopy"
+
+const Doc = `check for values of @mod:nocopy types copied by value.
+
+A type annotated by
+
+	//@mod:nocopy
+
+must not be copied after first use, like types containing sync.Mutex. This
+analyzer reports assignments, variable declarations, composite literal
+elements, function arguments, parameters, receivers, return statements and
+range variables that copy a value of such a type, or of a struct or array type
+containing it.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "nocopy",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer, modifier.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	c := &checker{
+		pass:      pass,
+		modifiers: pass.ResultOf[modifier.Analyzer].(*modifier.Result),
+	}
+
+	inspect.Preorder([]ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.CompositeLit)(nil),
+		(*ast.ReturnStmt)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.RangeStmt)(nil),
+	}, func(n ast.Node) {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			util.CheckAssign(pass, x, "assignment copies", c.report)
+		case *ast.ValueSpec:
+			for _, expr := range x.Values {
+				util.CheckCopy(pass, expr, "variable declaration copies", c.report)
+			}
+		case *ast.CompositeLit:
+			for _, expr := range x.Elts {
+				if kv, ok := expr.(*ast.KeyValueExpr); ok {
+					expr = kv.Value
+				}
+				util.CheckCopy(pass, expr, "literal copies", c.report)
+			}
+		case *ast.ReturnStmt:
+			for _, expr := range x.Results {
+				util.CheckCopy(pass, expr, "return copies", c.report)
+			}
+		case *ast.CallExpr:
+			if util.IsBuiltinOrConversion(pass, x) {
+				return
+			}
+			for _, expr := range x.Args {
+				util.CheckCopy(pass, expr, "call passes", c.report)
+			}
+		case *ast.FuncDecl:
+			if x.Recv != nil {
+				util.CheckFields(pass, x.Recv, "receiver passes", c.report)
+			}
+			util.CheckFields(pass, x.Type.Params, "parameter passes", c.report)
+		case *ast.FuncLit:
+			util.CheckFields(pass, x.Type.Params, "parameter passes", c.report)
+		case *ast.RangeStmt:
+			util.CheckRange(pass, x, "range var copies", c.report)
+		}
+	})
+	return nil, nil
+}
+
+type checker struct {
+	pass      *analysis.Pass
+	modifiers *modifier.Result
+}
+
+func (c *checker) report(pos token.Pos, what string, typ types.Type) {
+	path := c.nocopyPath(typ, make(map[types.Type]bool))
+	if path == nil {
+		return
+	}
+	c.pass.Reportf(pos, "%s value of nocopy type: %s", what, strings.Join(path, " contains "))
+}
+
+// nocopyPath returns the path from typ to a nocopy type it contains by value, or nil.
+func (c *checker) nocopyPath(typ types.Type, seen map[types.Type]bool) []string {
+	if seen[typ] {
+		return nil
+	}
+	seen[typ] = true
+	if named, ok := typ.(*types.Named); ok {
+		if _, ok := c.modifiers.Find(named.Origin().Obj(), directive); ok {
+			return []string{util.TypeString(c.pass, typ)}
+		}
+	}
+	switch x := typ.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < x.NumFields(); i++ {
+			if path := c.nocopyPath(x.Field(i).Type(), seen); path != nil {
+				return append([]string{util.TypeString(c.pass, typ)}, path...)
+			}
+		}
+	case *types.Array:
+		return c.nocopyPath(x.Elem(), seen)
+	}
+	return nil
+}
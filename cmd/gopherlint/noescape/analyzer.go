@@ -0,0 +1,252 @@
+package noescape
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/gopherd/tools/cmd/gopherlint/modifier"
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const directive = "noescape"
+
+const Doc = `check for @mod:noescape parameters that escape to the heap or a goroutine.
+
+A parameter annotated by
+
+	func f(
+		//@mod:noescape
+		p *T,
+	)
+
+promises the function does not retain it after returning. Parameters may also
+be listed by a directive of the function
+
+	//@mod:noescape param...
+
+This analyzer reports statements in the function body which store such a
+parameter, or a local variable holding it, in a package-level variable, a field
+or element reachable through a pointer, a map or a slice, send it on a
+channel, or pass or capture it in a go statement.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "noescape",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer, modifier.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	modifiers := pass.ResultOf[modifier.Analyzer].(*modifier.Result)
+
+	inspect.Preorder([]ast.Node{
+		(*ast.FuncDecl)(nil),
+	}, func(n ast.Node) {
+		decl := n.(*ast.FuncDecl)
+		if decl.Body == nil {
+			return
+		}
+		params := lookupParams(pass, modifiers, decl)
+		if len(params) > 0 {
+			checkBody(pass, decl.Body, params)
+		}
+	})
+	return nil, nil
+}
+
+// lookupParams returns noescape parameters of decl, mapped to themselves.
+func lookupParams(pass *analysis.Pass, modifiers *modifier.Result, decl *ast.FuncDecl) map[types.Object]types.Object {
+	var declared = make(map[string]types.Object)
+	var params = make(map[types.Object]types.Object)
+	for _, field := range decl.Type.Params.List {
+		for _, name := range field.Names {
+			obj := pass.TypesInfo.Defs[name]
+			if obj == nil {
+				continue
+			}
+			declared[name.Name] = obj
+			if _, ok := modifiers.Find(obj, directive); ok {
+				params[obj] = obj
+			}
+		}
+	}
+	m, ok := modifiers.Find(pass.TypesInfo.Defs[decl.Name], directive)
+	if !ok {
+		return params
+	}
+	for _, name := range m.Args() {
+		obj, ok := declared[name]
+		if !ok {
+			pass.Reportf(decl.Name.Pos(), "unknown parameter %s in %s directive of %s", name, directive, decl.Name.Name)
+			continue
+		}
+		params[obj] = obj
+	}
+	return params
+}
+
+// checkBody reports escapes of params in body. Local variables holding a
+// parameter, e.g. q in "q := p", are tracked as aliases of the parameter.
+func checkBody(pass *analysis.Pass, body *ast.BlockStmt, params map[types.Object]types.Object) {
+	addAliases(pass, body, params)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			if len(x.Lhs) != len(x.Rhs) {
+				return true
+			}
+			for i, rhs := range x.Rhs {
+				param := storedParam(pass, params, rhs)
+				if param == nil || !isHeapLocation(pass, x.Lhs[i]) {
+					continue
+				}
+				pass.Reportf(rhs.Pos(), "noescape parameter %s escapes: stored in a heap location", param.Name())
+			}
+		case *ast.SendStmt:
+			if param := storedParam(pass, params, x.Value); param != nil {
+				pass.Reportf(x.Value.Pos(), "noescape parameter %s escapes: sent on a channel", param.Name())
+			}
+		case *ast.GoStmt:
+			if ident, param := findParam(pass, params, x.Call); ident != nil {
+				pass.Reportf(ident.Pos(), "noescape parameter %s escapes: used by a goroutine", param.Name())
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// addAliases adds local variables of body which are assigned a value storing
+// one of params to params, until no more aliases are found.
+func addAliases(pass *analysis.Pass, body *ast.BlockStmt, params map[types.Object]types.Object) {
+	var add = func(lhs, rhs ast.Expr) bool {
+		ident, ok := util.Unparen(lhs).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		obj, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		if !ok || params[obj] != nil || obj.Pkg() == nil || obj.Parent() == obj.Pkg().Scope() {
+			return false
+		}
+		param := storedParam(pass, params, rhs)
+		if param == nil {
+			return false
+		}
+		params[obj] = param
+		return true
+	}
+	for changed := true; changed; {
+		changed = false
+		ast.Inspect(body, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.AssignStmt:
+				if len(x.Lhs) == len(x.Rhs) {
+					for i := range x.Lhs {
+						changed = add(x.Lhs[i], x.Rhs[i]) || changed
+					}
+				}
+			case *ast.ValueSpec:
+				if len(x.Names) == len(x.Values) {
+					for i := range x.Names {
+						changed = add(x.Names[i], x.Values[i]) || changed
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// storedParam returns the parameter stored by evaluating expr: the parameter
+// itself or its alias, an element appended to a slice or an element of a
+// composite literal.
+func storedParam(pass *analysis.Pass, params map[types.Object]types.Object, expr ast.Expr) types.Object {
+	switch x := util.Unparen(expr).(type) {
+	case *ast.Ident:
+		if param := params[pass.TypesInfo.Uses[x]]; param != nil {
+			return param
+		}
+	case *ast.UnaryExpr:
+		if x.Op == token.AND {
+			return storedParam(pass, params, x.X)
+		}
+	case *ast.CompositeLit:
+		for _, elt := range x.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			if obj := storedParam(pass, params, elt); obj != nil {
+				return obj
+			}
+		}
+	case *ast.CallExpr:
+		ident, ok := util.Unparen(x.Fun).(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		if builtin, ok := pass.TypesInfo.Uses[ident].(*types.Builtin); !ok || builtin.Name() != "append" {
+			return nil
+		}
+		for _, arg := range x.Args[1:] {
+			if obj := storedParam(pass, params, arg); obj != nil {
+				return obj
+			}
+		}
+	}
+	return nil
+}
+
+// isHeapLocation reports whether expr denotes a package-level variable or a
+// location reachable through a pointer, map or slice.
+func isHeapLocation(pass *analysis.Pass, expr ast.Expr) bool {
+	switch x := util.Unparen(expr).(type) {
+	case *ast.Ident:
+		obj, ok := pass.TypesInfo.ObjectOf(x).(*types.Var)
+		return ok && obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope()
+	case *ast.StarExpr:
+		return true
+	case *ast.SelectorExpr:
+		typ := pass.TypesInfo.TypeOf(x.X)
+		if typ == nil {
+			return isHeapLocation(pass, x.Sel) // package-qualified variable
+		}
+		if _, ok := typ.Underlying().(*types.Pointer); ok {
+			return true
+		}
+		return isHeapLocation(pass, x.X)
+	case *ast.IndexExpr:
+		typ := pass.TypesInfo.TypeOf(x.X)
+		if typ == nil {
+			return false
+		}
+		switch typ.Underlying().(type) {
+		case *types.Map, *types.Slice, *types.Pointer:
+			return true
+		}
+		return isHeapLocation(pass, x.X)
+	}
+	return false
+}
+
+// findParam returns the first identifier in node which refers to one of
+// params or their aliases, and the parameter.
+func findParam(pass *analysis.Pass, params map[types.Object]types.Object, node ast.Node) (*ast.Ident, types.Object) {
+	var found *ast.Ident
+	var param types.Object
+	ast.Inspect(node, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		if ident, ok := n.(*ast.Ident); ok && params[pass.TypesInfo.Uses[ident]] != nil {
+			found, param = ident, params[pass.TypesInfo.Uses[ident]]
+		}
+		return found == nil
+	})
+	return found, param
+}
@@ -0,0 +1,69 @@
+// Package plugin registers gopherlint analyzers as a golangci-lint module
+// plugin named "gopherlint", e.g. in .custom-gcl.yml:
+//
+//	plugins:
+//	  - module: github.com/gopherd/tools/cmd/gopherlint
+//	    import: github.com/gopherd/tools/cmd/gopherlint/plugin
+//	    version: latest
+//
+// and in .golangci.yml:
+//
+//	linters-settings:
+//	  custom:
+//	    gopherlint:
+//	      type: module
+//	      settings:
+//	        analyzers: [final, unusedresult]
+//	        settings:
+//	          unusedresult:
+//	            types: ["*github.com/gopherd/log.Context"]
+//
+// Settings have the same meaning as in a gopherlint configuration file.
+package plugin
+
+import (
+	"github.com/golangci/plugin-module-register/register"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/gopherd/tools/cmd/gopherlint/analyzers"
+	"github.com/gopherd/tools/cmd/gopherlint/config"
+)
+
+func init() {
+	register.Plugin("gopherlint", New)
+}
+
+// Settings of the plugin.
+type Settings struct {
+	// Analyzers lists enabled analyzers, all analyzers are enabled if empty.
+	Analyzers []string `json:"analyzers"`
+	// Settings maps analyzer names to values of their flags.
+	Settings map[string]map[string]any `json:"settings"`
+}
+
+type plugin struct {
+	settings Settings
+}
+
+// New creates the plugin by settings of golangci-lint.
+func New(settings any) (register.LinterPlugin, error) {
+	s, err := register.DecodeSettings[Settings](settings)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin{settings: s}, nil
+}
+
+// BuildAnalyzers implements register.LinterPlugin.
+func (p *plugin) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	cfg := &config.Config{
+		Analyzers: p.settings.Analyzers,
+		Settings:  p.settings.Settings,
+	}
+	return cfg.Apply(analyzers.All())
+}
+
+// GetLoadMode implements register.LinterPlugin.
+func (p *plugin) GetLoadMode() string {
+	return register.LoadModeTypesInfo
+}
@@ -0,0 +1,98 @@
+package plugin
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/golangci/plugin-module-register/register"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestBuildAnalyzers(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		settings any
+		want     []string          // names of analyzers, all analyzers if nil
+		flags    map[string]string // flags of analyzer unusedresult
+		wantErr  string
+	}{
+		{
+			name: "all",
+		},
+		{
+			name:     "listed",
+			settings: map[string]any{"analyzers": []any{"unusedresult", "final"}},
+			want:     []string{"unusedresult", "final"},
+		},
+		{
+			name: "settings",
+			settings: map[string]any{
+				"analyzers": []any{"unusedresult"},
+				"settings": map[string]any{
+					"unusedresult": map[string]any{"types": []any{"x.T", "*y.U"}},
+				},
+			},
+			want:  []string{"unusedresult"},
+			flags: map[string]string{"types": "*y.U,x.T"},
+		},
+		{
+			name:     "unknown analyzer",
+			settings: map[string]any{"analyzers": []any{"unknown"}},
+			wantErr:  `unknown analyzer "unknown"`,
+		},
+		{
+			name: "settings of unknown analyzer",
+			settings: map[string]any{
+				"settings": map[string]any{"unknown": map[string]any{"x": 1}},
+			},
+			wantErr: `settings of unknown analyzer "unknown"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.settings)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if mode := p.GetLoadMode(); mode != register.LoadModeTypesInfo {
+				t.Errorf("GetLoadMode() = %q, want %q", mode, register.LoadModeTypesInfo)
+			}
+			analyzers, err := p.BuildAnalyzers()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("BuildAnalyzers() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildAnalyzers: %v", err)
+			}
+			var names []string
+			for _, a := range analyzers {
+				names = append(names, a.Name)
+			}
+			if tt.want == nil {
+				if !slices.Contains(names, "final") || !slices.Contains(names, "suppress") {
+					t.Errorf("BuildAnalyzers() = %v, want all analyzers", names)
+				}
+			} else {
+				slices.Sort(names)
+				slices.Sort(tt.want)
+				if !slices.Equal(names, tt.want) {
+					t.Errorf("BuildAnalyzers() = %v, want %v", names, tt.want)
+				}
+			}
+			for name, want := range tt.flags {
+				i := slices.IndexFunc(analyzers, func(a *analysis.Analyzer) bool { return a.Name == "unusedresult" })
+				if got := analyzers[i].Flags.Lookup(name).Value.String(); got != want {
+					t.Errorf("flag %s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewInvalidSettings(t *testing.T) {
+	if _, err := New(map[string]any{"analyzers": "final"}); err == nil {
+		t.Error("New() error = nil, want an error decoding analyzers")
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gopherd/tools/cmd/gopherlint/analyzers"
+	"github.com/gopherd/tools/cmd/gopherlint/baseline"
+	"github.com/gopherd/tools/cmd/gopherlint/diff"
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+	"github.com/gopherd/tools/cmd/gopherlint/report"
+)
+
+// postProcess runs analyzers in a child process and processes findings
+// reported by it. It returns the exit code like multichecker: 0 for success,
+// 1 for errors and 3 for findings. Findings written in a machine-readable
+// format are not failures, like the -json output of multichecker.
+func postProcess(args []string) int {
+	var changes *diff.Changes
+	if flags.newFrom != "" && !flags.baselineWrite {
+		var err error
+		if changes, err = loadChanges(); err != nil {
+			fmt.Fprintf(os.Stderr, "gopherlint: %v\n", err)
+			return 1
+		}
+	}
+	list, err := findings.Run(childArgs(args))
+	if list == nil && err != nil {
+		fmt.Fprintf(os.Stderr, "gopherlint: %v\n", err)
+		return 1
+	}
+	var exitcode int
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		exitcode = 1 // analysis failed, at least partially
+	}
+
+	if changes != nil {
+		list = changes.Filter(list)
+	}
+
+	if flags.baselineWrite {
+		if flags.baseline == "" {
+			fmt.Fprintln(os.Stderr, "gopherlint: -baseline.write requires -baseline")
+			return 2
+		}
+		if err := baseline.Write(flags.baseline, list); err != nil {
+			fmt.Fprintf(os.Stderr, "gopherlint: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "gopherlint: wrote %d findings to %s\n", len(list), flags.baseline)
+		return exitcode
+	}
+	if flags.baseline != "" {
+		b, err := baseline.Load(flags.baseline)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gopherlint: %v\n", err)
+			return 1
+		}
+		list = b.Filter(list)
+	}
+
+	if flags.format != "text" {
+		if err := report.Write(os.Stdout, flags.format, workdir(), list, analyzers.All()); err != nil {
+			fmt.Fprintf(os.Stderr, "gopherlint: %v\n", err)
+			return 1
+		}
+		return exitcode
+	}
+	if err := report.Write(os.Stderr, "text", "", list, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "gopherlint: %v\n", err)
+		return 1
+	}
+	if exitcode == 0 && len(list) > 0 {
+		exitcode = 3
+	}
+	return exitcode
+}
+
+// workdir returns the current directory, which is the root of relative
+// paths in reports.
+func workdir() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	return dir
+}
+
+// loadChanges loads changes specified by flag -new-from.
+func loadChanges() (*diff.Changes, error) {
+	if flags.newFrom == "-" {
+		return diff.Parse(os.Stdin, diff.TopLevel(workdir()))
+	}
+	return diff.Git(flags.newFrom)
+}
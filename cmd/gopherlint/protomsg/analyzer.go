@@ -0,0 +1,416 @@
+package protomsg
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const Doc = `check for misuses of protobuf messages.
+
+A protobuf message is a struct type declared in a .pb.go file. This analyzer
+reports
+
+  - direct reads of a field through a pointer to a message, which panic if the
+    message is nil, while the generated getter GetX() returns the zero value.
+    A pointer is considered possibly nil unless the read is guarded by
+    "x != nil" in an enclosing if condition or on the left of &&, by
+    "x == nil" on the left of ||, or follows "if x == nil { return }" in the
+    same or an enclosing block. Local variables which are only assigned
+    &T{...} or new(T) are never nil;
+  - copies of a message by value, which copy its internal state including a
+    mutex, in assignments, variable declarations, function arguments,
+    parameters, return statements and range variables;
+  - comparisons of messages by ==, != or reflect.DeepEqual instead of
+    proto.Equal. Pointers to messages may be compared by == or != to check
+    whether they are the same message.
+
+Code in .pb.go files is not checked.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "protomsg",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	report := reporter(pass)
+
+	inspect.WithStack([]ast.Node{
+		(*ast.File)(nil),
+		(*ast.SelectorExpr)(nil),
+		(*ast.BinaryExpr)(nil),
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.ReturnStmt)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.RangeStmt)(nil),
+	}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch x := n.(type) {
+		case *ast.File:
+			return !util.IsProtobufFile(pass.Fset.Position(x.Pos()).Filename)
+		case *ast.SelectorExpr:
+			checkFieldRead(pass, x, stack)
+		case *ast.BinaryExpr:
+			checkComparison(pass, x)
+		case *ast.AssignStmt:
+			util.CheckAssign(pass, x, "assignment copies", report)
+		case *ast.ValueSpec:
+			for _, expr := range x.Values {
+				util.CheckCopy(pass, expr, "variable declaration copies", report)
+			}
+		case *ast.ReturnStmt:
+			for _, expr := range x.Results {
+				util.CheckCopy(pass, expr, "return copies", report)
+			}
+		case *ast.CallExpr:
+			if util.IsBuiltinOrConversion(pass, x) {
+				return true
+			}
+			checkDeepEqual(pass, x)
+			for _, expr := range x.Args {
+				util.CheckCopy(pass, expr, "call passes", report)
+			}
+		case *ast.FuncDecl:
+			if x.Recv != nil {
+				util.CheckFields(pass, x.Recv, "receiver passes", report)
+			}
+			util.CheckFields(pass, x.Type.Params, "parameter passes", report)
+		case *ast.FuncLit:
+			util.CheckFields(pass, x.Type.Params, "parameter passes", report)
+		case *ast.RangeStmt:
+			util.CheckRange(pass, x, "range var copies", report)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// isMessage reports whether typ is a protobuf message struct type.
+func isMessage(pass *analysis.Pass, typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	return util.IsProtobufFile(pass.Fset.Position(named.Obj().Pos()).Filename)
+}
+
+// isMessagePointer reports whether typ is a pointer to a protobuf message.
+func isMessagePointer(pass *analysis.Pass, typ types.Type) bool {
+	ptr, ok := typ.(*types.Pointer)
+	return ok && isMessage(pass, ptr.Elem())
+}
+
+// checkFieldRead reports x if it reads a field through a possibly nil
+// pointer to a message which has a getter of the field.
+func checkFieldRead(pass *analysis.Pass, x *ast.SelectorExpr, stack []ast.Node) {
+	selection := pass.TypesInfo.Selections[x]
+	if selection == nil || selection.Kind() != types.FieldVal || len(selection.Index()) != 1 {
+		return
+	}
+	typ := pass.TypesInfo.TypeOf(x.X)
+	if typ == nil || !isMessagePointer(pass, typ) {
+		return
+	}
+	getter := "Get" + x.Sel.Name
+	if obj, _, _ := types.LookupFieldOrMethod(typ, true, selection.Obj().Pkg(), getter); obj == nil {
+		return
+	}
+	if isWritten(x, stack) || isNilGuarded(pass, x.X, stack) || isNonNil(pass, x.X, stack) {
+		return
+	}
+	pass.Report(analysis.Diagnostic{
+		Pos:     x.Pos(),
+		End:     x.End(),
+		Message: "field " + x.Sel.Name + " of possibly nil protobuf message is read directly, use " + getter + "()",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message: "Use " + getter + "()",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     x.Sel.Pos(),
+				End:     x.Sel.End(),
+				NewText: []byte(getter + "()"),
+			}},
+		}},
+	})
+}
+
+// isWritten reports whether the field selected by x is written or referenced.
+func isWritten(x *ast.SelectorExpr, stack []ast.Node) bool {
+	if len(stack) < 2 {
+		return false
+	}
+	var expr ast.Node = x
+	var i = len(stack) - 2
+	for ; i >= 0; i-- {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+		expr = stack[i]
+	}
+	if i < 0 {
+		return false
+	}
+	switch parent := stack[i].(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range parent.Lhs {
+			if lhs == expr {
+				return true
+			}
+		}
+	case *ast.IncDecStmt:
+		return parent.X == expr
+	case *ast.UnaryExpr:
+		return parent.Op == token.AND
+	}
+	return false
+}
+
+// isNilGuarded reports whether x is an identifier checked by "x != nil"
+// in the condition of an enclosing if statement, or on the left of an
+// enclosing && expression, or by "x == nil" on the left of an enclosing ||
+// expression, or by "x == nil" in a preceding if statement of
+// an enclosing block which interrupts control flow, e.g. by return.
+func isNilGuarded(pass *analysis.Pass, x ast.Expr, stack []ast.Node) bool {
+	ident, ok := util.Unparen(x).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	obj := pass.TypesInfo.ObjectOf(ident)
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch x := stack[i].(type) {
+		case *ast.IfStmt:
+			if stack[i+1] == x.Body && checksNotNil(pass, x.Cond, obj) {
+				return true
+			}
+		case *ast.BinaryExpr:
+			if x.Op == token.LAND && stack[i+1] == x.Y && checksNotNil(pass, x.X, obj) {
+				return true
+			}
+			if x.Op == token.LOR && stack[i+1] == x.Y && checksNil(pass, x.X, obj) {
+				return true
+			}
+		case *ast.BlockStmt:
+			if returnsIfNil(pass, x.List, stack[i+1], obj) {
+				return true
+			}
+		case *ast.CaseClause:
+			if returnsIfNil(pass, x.Body, stack[i+1], obj) {
+				return true
+			}
+		case *ast.CommClause:
+			if returnsIfNil(pass, x.Body, stack[i+1], obj) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNonNil reports whether x is a local variable of the enclosing function
+// which is only assigned new values, i.e. &T{...} or new(T).
+func isNonNil(pass *analysis.Pass, x ast.Expr, stack []ast.Node) bool {
+	ident, ok := util.Unparen(x).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	obj := pass.TypesInfo.ObjectOf(ident)
+	var body *ast.BlockStmt
+	for _, n := range stack {
+		if fn, ok := n.(*ast.FuncDecl); ok {
+			body = fn.Body
+			break
+		} else if fn, ok := n.(*ast.FuncLit); ok {
+			body = fn.Body
+			break
+		}
+	}
+	if obj == nil || body == nil || obj.Pos() < body.Pos() || obj.Pos() >= body.End() {
+		return false // not a local variable, e.g. a parameter
+	}
+	var isObj = func(expr ast.Expr) bool {
+		ident, ok := util.Unparen(expr).(*ast.Ident)
+		return ok && pass.TypesInfo.ObjectOf(ident) == obj
+	}
+	var assigned, nonNil = false, true
+	var assign = func(lhs, rhs []ast.Expr) {
+		for i, expr := range lhs {
+			if !isObj(expr) {
+				continue
+			}
+			assigned = true
+			if len(lhs) != len(rhs) || !isNew(pass, rhs[i]) {
+				nonNil = false
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			assign(x.Lhs, x.Rhs)
+		case *ast.ValueSpec:
+			var lhs = make([]ast.Expr, len(x.Names))
+			for i, name := range x.Names {
+				lhs[i] = name
+			}
+			assign(lhs, x.Values)
+		case *ast.RangeStmt:
+			if x.Key != nil && isObj(x.Key) || x.Value != nil && isObj(x.Value) {
+				nonNil = false
+			}
+		case *ast.UnaryExpr:
+			if x.Op == token.AND && isObj(x.X) {
+				nonNil = false // it may be assigned through the pointer
+			}
+		}
+		return nonNil
+	})
+	return assigned && nonNil
+}
+
+// isNew reports whether expr is &T{...} or new(T).
+func isNew(pass *analysis.Pass, expr ast.Expr) bool {
+	switch x := util.Unparen(expr).(type) {
+	case *ast.UnaryExpr:
+		_, ok := util.Unparen(x.X).(*ast.CompositeLit)
+		return x.Op == token.AND && ok
+	case *ast.CallExpr:
+		ident, ok := util.Unparen(x.Fun).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		builtin, ok := pass.TypesInfo.Uses[ident].(*types.Builtin)
+		return ok && builtin.Name() == "new"
+	}
+	return false
+}
+
+// returnsIfNil reports whether a statement of list preceding stmt is an if
+// statement checking obj == nil whose body interrupts control flow, and obj
+// is not assigned between them.
+func returnsIfNil(pass *analysis.Pass, list []ast.Stmt, stmt ast.Node, obj types.Object) bool {
+	var i = len(list) - 1
+	for ; i >= 0 && list[i] != stmt; i-- {
+	}
+	for i--; i >= 0; i-- {
+		if x, ok := list[i].(*ast.IfStmt); ok && x.Init == nil && x.Else == nil && checksNil(pass, x.Cond, obj) &&
+			len(x.Body.List) > 0 && util.IsInterruptedStmt(pass, x.Body.List[len(x.Body.List)-1]) {
+			return true
+		}
+		if assigns(pass, list[i], obj) {
+			return false
+		}
+	}
+	return false
+}
+
+// assigns reports whether stmt assigns obj.
+func assigns(pass *analysis.Pass, stmt ast.Stmt, obj types.Object) bool {
+	var found bool
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok {
+			for _, lhs := range assign.Lhs {
+				if ident, ok := util.Unparen(lhs).(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == obj {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// checksNotNil reports whether cond implies obj != nil.
+func checksNotNil(pass *analysis.Pass, cond ast.Expr, obj types.Object) bool {
+	binary, ok := util.Unparen(cond).(*ast.BinaryExpr)
+	if !ok {
+		return false
+	}
+	switch binary.Op {
+	case token.LAND:
+		return checksNotNil(pass, binary.X, obj) || checksNotNil(pass, binary.Y, obj)
+	case token.NEQ:
+		return comparesWithNil(pass, binary, obj)
+	}
+	return false
+}
+
+// checksNil reports whether !cond implies obj != nil.
+func checksNil(pass *analysis.Pass, cond ast.Expr, obj types.Object) bool {
+	binary, ok := util.Unparen(cond).(*ast.BinaryExpr)
+	if !ok {
+		return false
+	}
+	switch binary.Op {
+	case token.LOR:
+		return checksNil(pass, binary.X, obj) || checksNil(pass, binary.Y, obj)
+	case token.EQL:
+		return comparesWithNil(pass, binary, obj)
+	}
+	return false
+}
+
+// comparesWithNil reports whether binary compares obj with nil.
+func comparesWithNil(pass *analysis.Pass, binary *ast.BinaryExpr, obj types.Object) bool {
+	x, y := util.Unparen(binary.X), util.Unparen(binary.Y)
+	if util.IsNil(pass, x) {
+		x, y = y, x
+	}
+	ident, ok := x.(*ast.Ident)
+	return ok && util.IsNil(pass, y) && pass.TypesInfo.ObjectOf(ident) == obj
+}
+
+// checkComparison reports comparisons of messages by == or !=. Pointers to
+// messages compared by == are identity checks, which are fine.
+func checkComparison(pass *analysis.Pass, x *ast.BinaryExpr) {
+	if x.Op != token.EQL && x.Op != token.NEQ {
+		return
+	}
+	for _, operand := range []ast.Expr{x.X, x.Y} {
+		typ := pass.TypesInfo.TypeOf(operand)
+		if typ != nil && isMessage(pass, typ) {
+			pass.Reportf(x.OpPos, "comparison of protobuf messages by %s, use proto.Equal", x.Op)
+			return
+		}
+	}
+}
+
+// checkDeepEqual reports calls of reflect.DeepEqual with messages or
+// pointers to messages, which compare internal states of messages.
+func checkDeepEqual(pass *analysis.Pass, call *ast.CallExpr) {
+	fn, _, _ := util.GetFunc(pass, util.Unparen(call.Fun))
+	if fn == nil || fn.FullName() != "reflect.DeepEqual" {
+		return
+	}
+	for _, arg := range call.Args {
+		typ := pass.TypesInfo.TypeOf(arg)
+		if typ != nil && (isMessage(pass, typ) || isMessagePointer(pass, typ)) {
+			pass.Reportf(call.Pos(), "comparison of protobuf messages by reflect.DeepEqual, use proto.Equal")
+			return
+		}
+	}
+}
+
+// reporter returns a reporter of copies of messages.
+func reporter(pass *analysis.Pass) util.CopyReporter {
+	return func(pos token.Pos, what string, typ types.Type) {
+		if isMessage(pass, typ) {
+			pass.Reportf(pos, "%s protobuf message %s by value", what, util.TypeString(pass, typ))
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package pure
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/gopherd/tools/cmd/gopherlint/modifier"
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const (
+	pureDirective       = "pure"
+	sideeffectDirective = "sideeffect"
+)
+
+const Doc = `check for @mod:pure functions that have side effects.
+
+A function annotated by
+
+	//@mod:pure
+
+must not write package-level variables, perform I/O calls or call non-pure
+functions. Calls of methods with pointer receivers on package-level variables
+and references of package-level variables are writes. A function is pure if it
+is annotated by @mod:pure and has been verified by this analyzer, or it is
+listed by -pure.funcs or belongs to one of the packages listed by -pure.pkgs,
+and is not annotated by
+
+	//@mod:sideeffect
+
+An annotated function failing the verification is not pure, so its callers
+are reported too.
+
+The set of I/O calls may be controlled using flag -pure.io, an item is either
+a function name such as "fmt.Println" or "(*os.File).Write", or a package
+path followed by ".*" such as "os.*".`
+
+var ioFuncs util.StringSetFlag
+var purePkgs util.StringSetFlag
+var pureFuncs util.StringSetFlag
+
+func init() {
+	ioFuncs.Set(strings.Join([]string{
+		"fmt.Print", "fmt.Printf", "fmt.Println",
+		"fmt.Fprint", "fmt.Fprintf", "fmt.Fprintln",
+		"fmt.Scan", "fmt.Scanf", "fmt.Scanln",
+		"time.Now", "time.Since", "time.Until", "time.Sleep",
+		"io.*", "os.*", "net.*", "net/http.*", "log.*",
+		"math/rand.*", "math/rand/v2.*", "crypto/rand.*",
+		"github.com/gopherd/log.*",
+	}, ","))
+	purePkgs.Set(strings.Join([]string{
+		"errors", "fmt", "math", "math/bits",
+		"strconv", "strings", "unicode", "unicode/utf8",
+	}, ","))
+	// Packages such as sort, slices and bytes have functions which modify
+	// their arguments, so only their functions which don't are pure.
+	pureFuncs.Set(strings.Join([]string{
+		"sort.Search", "sort.SearchInts", "sort.SearchStrings", "sort.SearchFloat64s",
+		"sort.IntsAreSorted", "sort.StringsAreSorted", "sort.Float64sAreSorted",
+		"slices.Contains", "slices.ContainsFunc", "slices.Equal", "slices.EqualFunc",
+		"slices.Index", "slices.IndexFunc", "slices.Compare", "slices.CompareFunc",
+		"slices.Max", "slices.Min", "slices.IsSorted", "slices.BinarySearch",
+		"maps.Equal", "maps.EqualFunc",
+		"bytes.Equal", "bytes.Compare", "bytes.Contains", "bytes.Count",
+		"bytes.HasPrefix", "bytes.HasSuffix", "bytes.Index", "bytes.IndexByte",
+		"bytes.LastIndex", "bytes.EqualFold",
+		"(*strings.Builder).Len", "(*strings.Builder).Cap", "(*strings.Builder).String",
+		"(*bytes.Buffer).Len", "(*bytes.Buffer).Cap", "(*bytes.Buffer).String",
+	}, ","))
+	Analyzer.Flags.Var(&ioFuncs, "io",
+		"comma-separated list of functions or packages (suffixed by .*) which perform I/O")
+	Analyzer.Flags.Var(&purePkgs, "pkgs",
+		"comma-separated list of packages whose functions are considered pure")
+	Analyzer.Flags.Var(&pureFuncs, "funcs",
+		"comma-separated list of functions which are considered pure, e.g. \"slices.Contains\"")
+}
+
+var Analyzer = &analysis.Analyzer{
+	Name:      "pure",
+	Doc:       Doc,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer, modifier.Analyzer},
+	FactTypes: []analysis.Fact{new(pureFact)},
+	Run:       run,
+}
+
+// pureFact is exported for functions annotated by @mod:pure and verified by the analyzer.
+type pureFact struct{}
+
+func (pureFact) AFact()         {}
+func (pureFact) String() string { return "pureFact" }
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	c := &checker{
+		pass:      pass,
+		modifiers: pass.ResultOf[modifier.Analyzer].(*modifier.Result),
+		verified:  make(map[*types.Func]bool),
+	}
+
+	var decls []*ast.FuncDecl
+	inspect.Preorder([]ast.Node{
+		(*ast.FuncDecl)(nil),
+	}, func(n ast.Node) {
+		decl := n.(*ast.FuncDecl)
+		obj, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+		if !ok || decl.Body == nil {
+			return
+		}
+		if _, ok := c.modifiers.Find(obj, pureDirective); !ok {
+			return
+		}
+		decls = append(decls, decl)
+		c.verified[obj] = true
+	})
+
+	// Functions of the package calling each other are assumed pure at first,
+	// and functions whose bodies are not pure are removed until none is left.
+	for changed := true; changed; {
+		changed = false
+		for _, decl := range decls {
+			obj := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+			if c.verified[obj] && !c.checkBody(decl.Name.Name, decl.Body, false) {
+				c.verified[obj] = false
+				changed = true
+			}
+		}
+	}
+	for _, decl := range decls {
+		obj := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+		if c.checkBody(decl.Name.Name, decl.Body, true) && c.verified[obj] {
+			pass.ExportObjectFact(obj, new(pureFact))
+		}
+	}
+	return nil, nil
+}
+
+type checker struct {
+	pass      *analysis.Pass
+	modifiers *modifier.Result
+	verified  map[*types.Func]bool // annotated functions of the package verified to be pure
+	report    bool
+}
+
+func (c *checker) reportf(pos token.Pos, format string, args ...any) {
+	if c.report {
+		c.pass.Reportf(pos, format, args...)
+	}
+}
+
+// checkBody reports side effects in body of function name if report is
+// true, and reports whether body is pure.
+func (c *checker) checkBody(name string, body *ast.BlockStmt, report bool) bool {
+	c.report = report
+	var pure = true
+	var checkWrite = func(expr ast.Expr) {
+		if v := c.packageVar(expr); v != nil {
+			c.reportf(expr.Pos(), "pure function %s writes package-level variable %s", name, v.Name())
+			pure = false
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			if x.Tok == token.DEFINE {
+				return true
+			}
+			for _, lhs := range x.Lhs {
+				checkWrite(lhs)
+			}
+		case *ast.IncDecStmt:
+			checkWrite(x.X)
+		case *ast.RangeStmt:
+			if x.Tok == token.ASSIGN {
+				for _, expr := range []ast.Expr{x.Key, x.Value} {
+					if expr != nil {
+						checkWrite(expr)
+					}
+				}
+			}
+		case *ast.UnaryExpr:
+			if x.Op != token.AND {
+				return true
+			}
+			if v := c.packageVar(x.X); v != nil {
+				c.reportf(x.Pos(), "pure function %s references package-level variable %s", name, v.Name())
+				pure = false
+			}
+		case *ast.CallExpr:
+			if !c.checkCall(name, x) || !c.checkReceiver(name, x) {
+				pure = false
+			}
+		}
+		return true
+	})
+	return pure
+}
+
+// checkReceiver reports the call of a method with pointer receiver on a
+// package-level variable, which may modify the variable, and reports whether
+// call doesn't. Methods verified to be pure or listed by -pure.funcs don't
+// modify their receivers.
+func (c *checker) checkReceiver(name string, call *ast.CallExpr) bool {
+	sel, ok := util.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+	selection := c.pass.TypesInfo.Selections[sel]
+	if selection == nil || selection.Kind() != types.MethodVal {
+		return true
+	}
+	fn := selection.Obj().(*types.Func)
+	if _, ok := fn.Type().(*types.Signature).Recv().Type().(*types.Pointer); !ok {
+		return true
+	}
+	v := c.packageVar(sel.X)
+	if v == nil || c.isVerified(fn) || pureFuncs[util.GetFuncNameBySign(fn, fn.Type().(*types.Signature))] {
+		return true
+	}
+	c.reportf(call.Pos(), "pure function %s modifies package-level variable %s by method %s", name, v.Name(), fn.Name())
+	return false
+}
+
+// packageVar returns the package-level variable which expr is a part of.
+func (c *checker) packageVar(expr ast.Expr) *types.Var {
+	for {
+		switch x := util.Unparen(expr).(type) {
+		case *ast.Ident:
+			v, ok := c.pass.TypesInfo.ObjectOf(x).(*types.Var)
+			if ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() {
+				return v
+			}
+			return nil
+		case *ast.SelectorExpr:
+			if _, ok := c.pass.TypesInfo.Uses[x.Sel].(*types.Var); ok && c.pass.TypesInfo.TypeOf(x.X) == nil {
+				expr = x.Sel // package-qualified variable
+			} else {
+				expr = x.X
+			}
+		case *ast.IndexExpr:
+			expr = x.X
+		case *ast.StarExpr:
+			expr = x.X
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *checker) checkCall(name string, call *ast.CallExpr) bool {
+	fun := util.Unparen(call.Fun)
+	if c.pass.TypesInfo.Types[fun].IsType() {
+		return true // a conversion, not a call
+	}
+	if _, ok := fun.(*ast.FuncLit); ok {
+		return true // body of function literal is checked as a part of the enclosing function
+	}
+	if ident, ok := fun.(*ast.Ident); ok {
+		if builtin, ok := c.pass.TypesInfo.Uses[ident].(*types.Builtin); ok {
+			if builtin.Name() == "print" || builtin.Name() == "println" {
+				c.reportf(call.Pos(), "pure function %s performs I/O call %s", name, builtin.Name())
+				return false
+			}
+			return true
+		}
+	}
+	fn, sig, _ := util.GetFunc(c.pass, fun)
+	if fn == nil {
+		c.reportf(call.Pos(), "pure function %s calls function value %s", name, types.ExprString(fun))
+		return false
+	}
+	callee := util.GetFuncNameBySign(fn, sig)
+	switch {
+	case isIO(fn, callee):
+		c.reportf(call.Pos(), "pure function %s performs I/O call %s", name, callee)
+	case c.hasDirective(fn, sideeffectDirective):
+		c.reportf(call.Pos(), "pure function %s calls function %s with side effects", name, callee)
+	case !c.isPure(fn, callee):
+		c.reportf(call.Pos(), "pure function %s calls non-pure function %s", name, callee)
+	default:
+		return true
+	}
+	return false
+}
+
+func (c *checker) hasDirective(fn *types.Func, name string) bool {
+	_, ok := c.modifiers.Find(fn, name)
+	return ok
+}
+
+func (c *checker) isPure(fn *types.Func, name string) bool {
+	if fn.Pkg() == nil {
+		return true // e.g. error.Error
+	}
+	if fn.Pkg() != c.pass.Pkg && (purePkgs[fn.Pkg().Path()] || pureFuncs[name]) {
+		return true
+	}
+	return c.isVerified(fn)
+}
+
+// isVerified reports whether fn is annotated by @mod:pure and verified.
+func (c *checker) isVerified(fn *types.Func) bool {
+	if fn.Pkg() == c.pass.Pkg {
+		return c.verified[fn.Origin()]
+	}
+	return c.pass.ImportObjectFact(fn.Origin(), new(pureFact))
+}
+
+func isIO(fn *types.Func, name string) bool {
+	if ioFuncs[name] {
+		return true
+	}
+	return fn.Pkg() != nil && ioFuncs[fn.Pkg().Path()+".*"]
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+)
+
+type checkstyle struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle writes findings in the Checkstyle XML format, findings
+// are grouped by file in order of the list.
+func writeCheckstyle(w io.Writer, dir string, list []findings.Finding) error {
+	var out = checkstyle{Version: "4.3"}
+	var index = make(map[string]int)
+	for _, f := range list {
+		name := relPath(dir, f.Filename)
+		i, ok := index[name]
+		if !ok {
+			i = len(out.Files)
+			index[name] = i
+			out.Files = append(out.Files, checkstyleFile{Name: name})
+		}
+		out.Files[i].Errors = append(out.Files[i].Errors, checkstyleError{
+			Line:     f.Line,
+			Column:   f.Column,
+			Severity: "warning",
+			Message:  f.Message,
+			Source:   "gopherlint." + f.Analyzer,
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
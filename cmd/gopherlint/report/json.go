@@ -0,0 +1,37 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+)
+
+type jsonFinding struct {
+	Analyzer string `json:"analyzer"`
+	Category string `json:"category,omitempty"`
+	Package  string `json:"package"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+}
+
+// writeJSON writes findings as a flat JSON array.
+func writeJSON(w io.Writer, dir string, list []findings.Finding) error {
+	var out = make([]jsonFinding, 0, len(list))
+	for _, f := range list {
+		out = append(out, jsonFinding{
+			Analyzer: f.Analyzer,
+			Category: f.Category,
+			Package:  f.Package,
+			File:     relPath(dir, f.Filename),
+			Line:     f.Line,
+			Column:   f.Column,
+			Message:  f.Message,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(out)
+}
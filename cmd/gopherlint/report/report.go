@@ -0,0 +1,52 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+)
+
+// Formats lists supported output formats.
+var Formats = []string{"text", "json", "sarif", "checkstyle"}
+
+// Write writes findings reported by analyzers to w in format. Paths of
+// files are written relative to dir if possible.
+func Write(w io.Writer, format, dir string, list []findings.Finding, analyzers []*analysis.Analyzer) error {
+	switch format {
+	case "text":
+		for _, f := range list {
+			if _, err := fmt.Fprintln(w, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		return writeJSON(w, dir, list)
+	case "sarif":
+		return writeSARIF(w, dir, list, analyzers)
+	case "checkstyle":
+		return writeCheckstyle(w, dir, list)
+	}
+	return fmt.Errorf("unknown format %q, supported formats: %s", format, strings.Join(Formats, ", "))
+}
+
+// relPath returns slash-separated path of filename relative to dir, or
+// filename itself if it's not in dir.
+func relPath(dir, filename string) string {
+	rel, err := filepath.Rel(dir, filename)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(filename)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// summary returns the first line of an analyzer's documentation.
+func summary(doc string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(doc), "\n")
+	return strings.TrimSpace(line)
+}
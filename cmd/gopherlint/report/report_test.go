@@ -0,0 +1,76 @@
+package report_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+	"github.com/gopherd/tools/cmd/gopherlint/report"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestWrite(t *testing.T) {
+	dir := filepath.FromSlash("/work")
+	list := []findings.Finding{
+		{
+			Analyzer: "final",
+			Package:  "example.com/a",
+			Filename: filepath.FromSlash("/work/a/a.go"),
+			Line:     12,
+			Column:   3,
+			Message:  "cannot assign a value to final variable x",
+		},
+		{
+			Analyzer: "visibility",
+			Package:  "example.com/a",
+			Category: "import",
+			Filename: filepath.FromSlash("/work/a/b c#1.go"),
+			Line:     4,
+			Message:  `import of "example.com/b/internal" is not allowed`,
+		},
+		{
+			Analyzer: "deadmod",
+			Package:  "example.com/c",
+			Filename: filepath.FromSlash("/other/c.go"),
+			Message:  "file-level finding without position",
+		},
+	}
+	analyzers := []*analysis.Analyzer{
+		{Name: "final", Doc: "check for assignments to final variables.\n\nDetails of final."},
+		{Name: "visibility", Doc: "check for invisible uses."},
+	}
+	for _, format := range report.Formats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := report.Write(&buf, format, dir, list, analyzers); err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("..", "testdata", "report", format+".golden")
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("%s output:\n%s\nwant:\n%s", format, got, want)
+			}
+		})
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := report.Write(new(bytes.Buffer), "xml", "", nil, nil); err == nil {
+		t.Error("Write succeeded, want error of unknown format")
+	}
+}
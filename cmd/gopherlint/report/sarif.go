@@ -0,0 +1,158 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/gopherd/tools/cmd/gopherlint/findings"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	srcRoot      = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLoc `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult               `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	FullDescription  sarifMessage `json:"fullDescription"`
+	DefaultConfig    sarifConfig  `json:"defaultConfiguration"`
+}
+
+type sarifConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLoc `json:"artifactLocation"`
+	Region           *sarifRegion     `json:"region,omitempty"`
+}
+
+type sarifArtifactLoc struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// writeSARIF writes findings as a SARIF 2.1.0 log, with a rule for each
+// analyzer described by its documentation. Files in dir are located
+// relative to %SRCROOT% which is dir.
+func writeSARIF(w io.Writer, dir string, list []findings.Finding, analyzers []*analysis.Analyzer) error {
+	var run = sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gopherlint",
+			InformationURI: "https://github.com/gopherd/tools",
+		}},
+		OriginalURIBaseIDs: map[string]sarifArtifactLoc{
+			srcRoot: {URI: fileURI(dir) + "/"},
+		},
+		Results: make([]sarifResult, 0, len(list)),
+	}
+	var ruleIndex = make(map[string]int)
+	for _, a := range analyzers {
+		ruleIndex[a.Name] = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               a.Name,
+			Name:             a.Name,
+			ShortDescription: sarifMessage{Text: summary(a.Doc)},
+			FullDescription:  sarifMessage{Text: strings.TrimSpace(a.Doc)},
+			DefaultConfig:    sarifConfig{Level: "warning"},
+		})
+	}
+	for _, f := range list {
+		index, ok := ruleIndex[f.Analyzer]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndex[f.Analyzer] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:            f.Analyzer,
+				Name:          f.Analyzer,
+				DefaultConfig: sarifConfig{Level: "warning"},
+			})
+		}
+		var location = sarifPhysicalLocation{ArtifactLocation: sarifArtifactLoc{URI: fileURI(f.Filename)}}
+		if rel := relPath(dir, f.Filename); !filepath.IsAbs(filepath.FromSlash(rel)) {
+			location.ArtifactLocation = sarifArtifactLoc{
+				URI:       (&url.URL{Path: rel}).String(),
+				URIBaseID: srcRoot,
+			}
+		}
+		if f.Line > 0 {
+			location.Region = &sarifRegion{
+				StartLine:   f.Line,
+				StartColumn: f.Column,
+			}
+		} // otherwise the finding is about the whole file
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    f.Analyzer,
+			RuleIndex: index,
+			Level:     "warning",
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
+
+func fileURI(filename string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(filename)}
+	if !strings.HasPrefix(u.Path, "/") {
+		u.Path = "/" + u.Path // e.g. C:/path on windows
+	}
+	return u.String()
+}
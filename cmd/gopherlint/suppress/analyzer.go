@@ -0,0 +1,348 @@
+package suppress
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/gopherd/tools/cmd/gopherlint/modifier"
+)
+
+const (
+	ignoreDirective     = "//gopherlint:ignore"
+	fileIgnoreDirective = "//gopherlint:file-ignore"
+	lintDisable         = "lint-disable"
+)
+
+const Doc = `check for malformed //gopherlint:ignore directives.
+
+A diagnostic of any analyzer wrapped by Wrap can be suppressed by
+
+	//gopherlint:ignore analyzer[,analyzer...] reason
+
+A directive at the end of a line suppresses diagnostics on that line, and a
+directive on its own line suppresses diagnostics in the statement or
+declaration starting on the next line. A directive
+
+	//gopherlint:file-ignore analyzer[,analyzer...] reason
+
+suppresses diagnostics in the whole file, and a modifier
+
+	//@mod:lint-disable analyzer[,analyzer...]
+
+suppresses diagnostics in the declarations it applies to. A directive which
+suppresses nothing is reported by the analyzer it names.
+
+This analyzer reports directives without a reason or naming unknown analyzers.`
+
+// New wraps analyzers by Wrap and returns the suppress analyzer, which
+// reports directives naming analyzers other than them.
+func New(analyzers ...*analysis.Analyzer) *analysis.Analyzer {
+	Wrap(analyzers...)
+	known := make(map[string]bool)
+	for _, a := range analyzers {
+		known[a.Name] = true
+	}
+	return &analysis.Analyzer{
+		Name: "suppress",
+		Doc:  Doc,
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			return run(pass, known)
+		},
+	}
+}
+
+// wrapped holds analyzers wrapped by Wrap.
+var wrapped = make(map[*analysis.Analyzer]bool)
+
+func run(pass *analysis.Pass, known map[string]bool) (interface{}, error) {
+	for _, file := range pass.Files {
+		for _, d := range parseDirectives(pass, file) {
+			if d.malformed {
+				pass.Reportf(d.comment.Pos(), "malformed directive, expect %q", strings.TrimPrefix(d.prefix, "//")+" analyzer[,analyzer...] reason")
+				continue
+			}
+			for _, name := range d.checks {
+				if !known[name] {
+					pass.Reportf(d.comment.Pos(), "unknown analyzer %q in %s directive", name, strings.TrimPrefix(d.prefix, "//"))
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Wrap wraps analyzers so that their diagnostics can be suppressed by
+// directives, an analyzer is wrapped once however many times Wrap is called.
+func Wrap(analyzers ...*analysis.Analyzer) {
+	for _, a := range analyzers {
+		if wrapped[a] {
+			continue
+		}
+		wrapped[a] = true
+		wrap(a)
+	}
+}
+
+func wrap(a *analysis.Analyzer) {
+	var runFunc = a.Run
+	if !requires(a, modifier.Analyzer) {
+		a.Requires = append(a.Requires, modifier.Analyzer)
+	}
+	a.Run = func(pass *analysis.Pass) (interface{}, error) {
+		var directives []*directive
+		for _, file := range pass.Files {
+			for _, d := range parseDirectives(pass, file) {
+				if !d.malformed && d.names(a.Name) {
+					directives = append(directives, d)
+				}
+			}
+		}
+		modifiers := pass.ResultOf[modifier.Analyzer].(*modifier.Result)
+		report := pass.Report
+		pass.Report = func(diag analysis.Diagnostic) {
+			suppressed := false
+			for _, d := range directives {
+				if d.matches(pass.Fset, diag.Pos) {
+					d.used = true
+					suppressed = true
+				}
+			}
+			if !suppressed && !lintDisabled(pass, modifiers, a.Name, diag.Pos) {
+				report(diag)
+			}
+		}
+		result, err := runFunc(pass)
+		pass.Report = report
+		if err != nil {
+			return result, err
+		}
+		for _, d := range directives {
+			if !d.used {
+				reportUnused(pass, a.Name, d)
+			}
+		}
+		return result, nil
+	}
+}
+
+func requires(a, dep *analysis.Analyzer) bool {
+	for _, x := range a.Requires {
+		if x == dep {
+			return true
+		}
+	}
+	return false
+}
+
+func reportUnused(pass *analysis.Pass, name string, d *directive) {
+	diag := analysis.Diagnostic{
+		Pos:     d.comment.Pos(),
+		End:     d.comment.End(),
+		Message: "unused " + strings.TrimPrefix(d.prefix, "//") + " directive for " + name,
+	}
+	if len(d.checks) == 1 {
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: "Remove the directive",
+			TextEdits: []analysis.TextEdit{{
+				Pos: d.comment.Pos(),
+				End: d.comment.End(),
+			}},
+		}}
+	}
+	pass.Report(diag)
+}
+
+// lintDisabled reports whether analyzer name is disabled at pos by a
+// lint-disable modifier of the enclosing top-level declaration.
+func lintDisabled(pass *analysis.Pass, modifiers *modifier.Result, name string, pos token.Pos) bool {
+	for _, file := range pass.Files {
+		if pos < file.Pos() || pos >= file.End() {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if pos < decl.Pos() || pos >= decl.End() {
+				continue
+			}
+			for _, ident := range declNames(decl, pos) {
+				m, ok := modifiers.Find(pass.TypesInfo.Defs[ident], lintDisable)
+				if ok && slices.Contains(splitChecks(m.Args()), name) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// declNames returns names declared by decl, or by the spec of decl containing pos.
+func declNames(decl ast.Decl, pos token.Pos) []*ast.Ident {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		return []*ast.Ident{decl.Name}
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			if pos < spec.Pos() || pos >= spec.End() {
+				continue
+			}
+			switch spec := spec.(type) {
+			case *ast.ValueSpec:
+				return spec.Names
+			case *ast.TypeSpec:
+				return []*ast.Ident{spec.Name}
+			}
+		}
+	}
+	return nil
+}
+
+type directive struct {
+	comment   *ast.Comment
+	prefix    string
+	checks    []string
+	malformed bool
+	used      bool
+
+	// scope of directive: the whole file, a line or a range of positions
+	file       *token.File
+	wholeFile  bool
+	line       int
+	start, end token.Pos
+}
+
+func (d *directive) names(name string) bool {
+	return slices.Contains(d.checks, name)
+}
+
+func (d *directive) matches(fset *token.FileSet, pos token.Pos) bool {
+	if !pos.IsValid() || fset.File(pos) != d.file {
+		return false
+	}
+	switch {
+	case d.wholeFile:
+		return true
+	case d.line > 0:
+		return d.file.Line(pos) == d.line
+	default:
+		return d.start <= pos && pos < d.end
+	}
+}
+
+func parseDirectives(pass *analysis.Pass, file *ast.File) []*directive {
+	var directives []*directive
+	var lines map[int]token.Pos // first position of code in each line
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			d := parseDirective(comment)
+			if d == nil {
+				continue
+			}
+			d.file = pass.Fset.File(comment.Pos())
+			directives = append(directives, d)
+			if d.malformed || d.prefix == fileIgnoreDirective {
+				d.wholeFile = true
+				continue
+			}
+			if lines == nil {
+				lines = codeLines(d.file, file)
+			}
+			line := d.file.Line(comment.Pos())
+			if pos, ok := lines[line]; ok && pos < comment.Pos() {
+				d.line = line // trailing comment
+				continue
+			}
+			node := nodeAtLine(d.file, file, d.file.Line(group.End())+1)
+			if node == nil {
+				d.line = d.file.Line(group.End()) + 1
+			} else {
+				d.start, d.end = node.Pos(), node.End()
+			}
+		}
+	}
+	return directives
+}
+
+func parseDirective(comment *ast.Comment) *directive {
+	var d = &directive{comment: comment}
+	var rest string
+	var ok bool
+	for _, prefix := range []string{ignoreDirective, fileIgnoreDirective} {
+		if rest, ok = strings.CutPrefix(comment.Text, prefix); ok && (rest == "" || rest[0] == ' ') {
+			d.prefix = prefix
+			break
+		}
+	}
+	if d.prefix == "" {
+		return nil
+	}
+	fields := strings.Fields(rest)
+	if len(fields) < 2 {
+		d.malformed = true
+		return d
+	}
+	d.checks = splitChecks(fields[:1])
+	return d
+}
+
+// codeLines returns the first position of code in each line of file.
+func codeLines(tokFile *token.File, file *ast.File) map[int]token.Pos {
+	var lines = make(map[int]token.Pos)
+	var add = func(pos token.Pos) {
+		if !pos.IsValid() {
+			return
+		}
+		line := tokFile.Line(pos)
+		if first, ok := lines[line]; !ok || pos < first {
+			lines[line] = pos
+		}
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil, *ast.File, *ast.Comment, *ast.CommentGroup:
+			return n != nil
+		}
+		add(n.Pos())
+		add(n.End() - 1)
+		return true
+	})
+	return lines
+}
+
+// nodeAtLine returns the outermost node starting at line.
+func nodeAtLine(tokFile *token.File, file *ast.File, line int) ast.Node {
+	var found ast.Node
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found != nil || n == nil {
+			return false
+		}
+		switch n.(type) {
+		case *ast.File, *ast.CommentGroup, *ast.Comment:
+			return true
+		}
+		if tokFile.Line(n.End()) < line {
+			return false
+		}
+		if tokFile.Line(n.Pos()) == line {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+func splitChecks(args []string) []string {
+	var checks []string
+	for _, arg := range args {
+		for _, name := range strings.Split(arg, ",") {
+			if name != "" {
+				checks = append(checks, name)
+			}
+		}
+	}
+	return checks
+}
@@ -0,0 +1,33 @@
+package suppress_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/gopherd/tools/cmd/gopherlint/suppress"
+	"github.com/gopherd/tools/cmd/gopherlint/unusedresult"
+)
+
+func testdata(t *testing.T) string {
+	dir, err := filepath.Abs("../testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestAnalyzer(t *testing.T) {
+	a := *unusedresult.Analyzer
+	analysistest.Run(t, testdata(t), suppress.New(&a), "suppress/malformed")
+}
+
+func TestWrap(t *testing.T) {
+	if err := unusedresult.Analyzer.Flags.Set("types", "*suppress/a.user"); err != nil {
+		t.Fatal(err)
+	}
+	a := *unusedresult.Analyzer
+	suppress.Wrap(&a)
+	analysistest.RunWithSuggestedFixes(t, testdata(t), &a, "suppress/a")
+}
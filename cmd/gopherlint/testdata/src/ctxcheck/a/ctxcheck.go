@@ -0,0 +1,75 @@
+package a
+
+import (
+	"context"
+	"time"
+)
+
+func work(ctx context.Context) {}
+
+func _(ctx context.Context) {
+	go work(context.Background()) // want `goroutine does not receive the context of the enclosing function`
+
+	go func() { // want `goroutine does not receive the context of the enclosing function`
+		work(context.TODO())
+	}()
+
+	// It's ok
+	go work(ctx)
+
+	// It's ok because of the context is derived
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go work(child)
+	var value = context.WithValue(child, "key", 1)
+	go func() {
+		work(value)
+	}()
+
+	// It's ok because of the function literal has its own context
+	_ = func(ctx context.Context) {
+		go work(ctx)
+	}
+}
+
+// It's ok because of the function has no context
+func _() {
+	go work(context.Background())
+}
+
+// It's ok because of the context is blank, e.g. to implement an interface
+func _(_ context.Context) {
+	go work(context.Background())
+}
+
+func _(ctx context.Context, ok bool) error {
+	child, cancel := context.WithTimeout(ctx, time.Second) // want `the cancel function cancel returned by context.WithTimeout is not called on all paths`
+	if !ok {
+		return nil
+	}
+	cancel()
+	work(child)
+	return nil
+}
+
+func _(ctx context.Context) {
+	child, _ := context.WithCancel(ctx) // want `the cancel function returned by context.WithCancel is discarded`
+	work(child)
+}
+
+func _(ctx context.Context) {
+	var child, cancel = context.WithDeadline(ctx, time.Now()) // want `the cancel function cancel returned by context.WithDeadline is not called on all paths`
+	if child.Err() != nil {
+		cancel()
+	}
+}
+
+func _(ctx context.Context, ok bool) (context.Context, context.CancelFunc) {
+	// It's ok because of the cancel function is called or returned on all paths
+	child, cancel := context.WithCancel(ctx)
+	if !ok {
+		cancel()
+		panic("not ok")
+	}
+	return child, cancel
+}
@@ -0,0 +1,39 @@
+//@mod:package finall // want `unknown directive @mod:finall`
+package a
+
+type T struct{}
+
+// It's ok because of finalconst reports the variable
+//@mod:final
+var limit = 10
+
+//@mod:!final
+var negated = 1
+
+//@mod:custom
+var custom = 1
+
+//@mod:finl // want `unknown directive @mod:finl`
+var typo = 1
+
+func f() {
+	//@mod:purr // want `unknown directive @mod:purr`
+	_ = typo
+}
+
+// It's ok because of pure functions may be called by other pure functions
+// even if they have no results.
+//@mod:pure
+func pureWithoutResults(x int) {
+	_ = x
+}
+
+//@mod:nilnil
+func nilnilWithoutError() *T { // want `@mod:nilnil has no effect on nilnilWithoutError which does not return \(T, error\)`
+	return nil
+}
+
+//@mod:nilnil
+func nilnilWithError() (*T, error) {
+	return nil, nil
+}
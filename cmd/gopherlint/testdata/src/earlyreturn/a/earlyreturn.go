@@ -0,0 +1,111 @@
+package a
+
+import "errors"
+
+func use(...any) {}
+
+func _(ok bool) error {
+	if !ok {
+		return errors.New("not ok")
+	} else { // want `if block ends with a return statement, so drop this else and outdent its block`
+		// the happy path
+		use(ok)
+	}
+	return nil
+}
+
+func _(list []int) {
+	for _, x := range list {
+		if x < 0 {
+			continue
+		} else if x == 0 { // want `if block ends with a continue statement, so drop this else and outdent its block`
+			use(x)
+		}
+	}
+}
+
+func _(ok bool) {
+	if !ok {
+		panic("not ok")
+	} else { use(ok) } // want `if block ends with a terminating call statement, so drop this else and outdent its block`
+}
+
+func _(ok bool) {
+	x := 1
+	if !ok {
+		return
+	} else { // want `if block ends with a return statement, so drop this else and outdent its block`
+		// no fix because of x would be redeclared
+		x := 2
+		use(x)
+	}
+	use(x)
+}
+
+func _(ok bool, unlock func()) {
+	if !ok {
+		return
+	} else { // want `if block ends with a return statement, so drop this else and outdent its block`
+		// the deferred call still runs when the function returns
+		defer unlock()
+		use(ok)
+	}
+}
+
+func _(ok bool) {
+	if !ok {
+		return
+	} else { // want `if block ends with a return statement, so drop this else and outdent its block`
+		use(`raw
+	string`, "x")
+	}
+}
+
+func _(ok bool) {
+	// It's ok because of the if block doesn't end with return
+	if !ok {
+		use(ok)
+	} else {
+		use(ok)
+	}
+
+	// It's ok because of the init statement
+	if err := errors.New("x"); err != nil {
+		return
+	} else {
+		use(err)
+	}
+}
+
+func _(a, b, c bool) {
+	use(a)
+	if a { // want `happy path is nested 3 levels deep, invert the conditions and return early`
+		if b {
+			if c {
+				use(a, b, c)
+			}
+		}
+	}
+}
+
+func _(list []bool) {
+	for _, a := range list {
+		if a { // want `happy path is nested 3 levels deep, invert the conditions and continue early`
+			use(a)
+			if !a {
+				if a {
+					use(a)
+				}
+			}
+		}
+	}
+}
+
+func _(a, b bool) {
+	// It's ok because of the happy path is not nested deeply
+	if a {
+		if b {
+			use(a, b)
+		}
+	}
+}
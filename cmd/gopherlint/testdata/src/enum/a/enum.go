@@ -0,0 +1,35 @@
+package a
+
+import (
+	"enum/b"
+)
+
+//@mod:enum
+type state int // want state:"enumFact"
+
+const (
+	idle state = iota
+	running
+	stopped
+)
+
+func _(s state, c b.Color) {
+	switch s { // want `missing cases in switch of enum type state: stopped`
+	case idle, running:
+	}
+
+	// It's ok
+	switch s {
+	case idle:
+	default:
+	}
+
+	switch c { // want `missing cases in switch of enum type enum/b.Color: Green, Blue`
+	case b.Default:
+	}
+
+	// It's ok
+	switch c {
+	case b.Red, b.Green, b.Blue:
+	}
+}
@@ -0,0 +1,11 @@
+package b
+
+//@mod:enum
+type Color int // want Color:"enumFact"
+
+const (
+	Red Color = iota
+	Green
+	Blue
+	Default = Red
+)
@@ -0,0 +1,69 @@
+package a
+
+import (
+	"final/b"
+)
+
+//@mod:final
+var finalSingle = 1 // want finalSingle:"finalDeclFact"
+
+// ....
+//
+//@mod:final
+//
+// balabala ...
+var (
+	finalGroup1 = "hello" // want finalGroup1:"finalDeclFact"
+	finalGroup2 = "world" // want finalGroup2:"finalDeclFact"
+	_           = "xxx"
+)
+
+//@mod:final
+var finalArray = [2]int{1, 2} // want finalArray:"finalDeclFact"
+
+func _() {
+	b.Final = 2 // want `cannot assign a value to final variable Final`
+
+	finalSingle = 2 // want `cannot assign a value to final variable finalSingle`
+	(finalSingle) = 3 // want `cannot assign a value to final variable finalSingle`
+
+	finalGroup1 = "h" // want `cannot assign a value to final variable finalGroup1`
+	finalGroup2 = "w" // want `cannot assign a value to final variable finalGroup2`
+	_ = "x"
+
+	finalArray[0] = 3 // want `cannot assign a value to final variable finalArray`
+
+	//@mod:final
+	//
+	var x int
+	x = 2 // want `cannot assign a value to final variable x`
+	unused(&x) // want `cannot reference final variable x`
+	x, y := 3, 4 // want `cannot assign a value to final variable x`
+	unused(y)
+
+	var z int //@mod:final
+	// It's ok because of @mod:final must be a document comment instead of line comment
+	z = 1
+	unused(z)
+
+	b.User.Reset() // want `cannot reference final variable User`
+
+	// It's ok
+	b.UserPtr.Reset()
+	b.UserPtr.Id = 3
+
+	//@mod:final
+	var slice = []int{1, 2}
+	// It's ok
+	slice[0] = 2
+	unused(&slice) // want `cannot reference final variable slice`
+
+	//@mod:final
+	var users = []b.UserInfo{
+		{Id: 1},
+	}
+	// It's ok
+	users[0].Id = 2
+}
+
+func unused(a ...interface{}) {}
@@ -0,0 +1,25 @@
+// Every package-level variable declared in this file is final.
+//
+//@mod:final
+package b
+
+var MaxPlayers = 100 // want MaxPlayers:"finalDeclFact"
+
+var (
+	MinPlayers = 2 // want MinPlayers:"finalDeclFact"
+
+	//@mod:!final
+	Mutable = 0
+)
+
+func _() {
+	MaxPlayers = 200 // want `cannot assign a value to final variable MaxPlayers`
+
+	// It's ok because of @mod:!final overrides the file scope directive
+	Mutable = 1
+
+	//@mod:final
+	var local = 1
+	local = 2 // want `cannot assign a value to final variable local`
+	_ = local
+}
@@ -1,7 +1,7 @@
 package b
 
 //@mod:final
-var Final = 1
+var Final = 1 // want Final:"finalDeclFact"
 
 type UserInfo struct {
 	Id   int
@@ -27,21 +27,19 @@ func (user UserInfo) NonPointerReceiver() int {
 }
 
 //@mod:final
-var User = UserInfo{Id: 1}
+var User = UserInfo{Id: 1} // want User:"finalDeclFact"
 
 //@mod:final
-var UserPtr = &UserInfo{Id: 2}
+var UserPtr = &UserInfo{Id: 2} // want UserPtr:"finalDeclFact"
 
 var otherUser = UserInfo{Id: 3}
 
 func ResetUser(id int) {
-	// cannot assign a value to final variable User
-	User = UserInfo{Id: id}
+	User = UserInfo{Id: id} // want `cannot assign a value to final variable User`
 }
 
 func _() {
-	// cannot reference final variable User
-	User.Reset()
+	User.Reset() // want `cannot reference final variable User`
 
 	// It's ok
 	UserPtr.Reset()
@@ -58,13 +56,10 @@ func _() {
 	// It's ok
 	otherUser.Reset()
 
-	// cannot assign a value to final variable User
-	User.Id = 0
+	User.Id = 0 // want `cannot assign a value to field of final variable User`
 
-	// cannot assign a value to field of final variable User
-	User.Info.Name = "new name"
+	User.Info.Name = "new name" // want `cannot assign a value to field of final variable User`
 
-	// cannot reference field of final variable User
-	var nameptr = &User.Info.Name
+	var nameptr = &User.Info.Name // want `cannot reference field of final variable User`
 	_ = nameptr
 }
@@ -0,0 +1,69 @@
+package a
+
+import (
+	"errors"
+	"time"
+)
+
+type T struct{}
+
+type counter int
+
+func (c *counter) inc() { *c++ }
+
+//@mod:final
+var limit = 10 // want `final variable limit is never modified, declare it as a constant`
+
+//@mod:final
+var name string = "gopher" // want `final variable name is never modified, declare it as a constant`
+
+//@mod:final
+var (
+	width  = 1.5 // want `final variable width is never modified, declare it as a constant`
+	height = 2   // want `final variable height is never modified, declare it as a constant`
+)
+
+//@mod:final
+var (
+	first  = 1 // want `final variable first is never modified, declare it as a constant`
+	second = errors.New("second")
+)
+
+//@mod:final
+var timeout = 3 * time.Second // want `final variable timeout is never modified, declare it as a constant`
+
+//@mod:final
+var x, y = 1, 2 // want `final variable x is never modified, declare it as a constant` `final variable y is never modified, declare it as a constant`
+
+//@mod:final
+var z, s = 1, "s" // want `final variable z is never modified, declare it as a constant` `final variable s is never modified, declare it as a constant`
+
+var notFinal = 1
+
+//@mod:final
+var assigned = 1
+
+//@mod:final
+var referenced = 1
+
+//@mod:final
+var incremented = 1
+
+//@mod:final
+var computed = len(errors.New("x").Error())
+
+//@mod:final
+var pointer = &T{}
+
+//@mod:final
+var count counter = 1
+
+func f() {
+	assigned = 2
+	_ = &referenced
+	incremented++
+	count.inc()
+	//@mod:final
+	var local = 1 // want `final variable local is never modified, declare it as a constant`
+	_ = local
+}
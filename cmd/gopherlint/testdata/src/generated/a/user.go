@@ -0,0 +1,11 @@
+package a
+
+type user struct {
+}
+
+func (u *user) self() *user { return u }
+
+func _() {
+	var u user
+	u.self() // want `result of \(generated/a.user\).self call not used`
+}
@@ -0,0 +1,23 @@
+// Package log is a minimal fake of github.com/gopherd/log for testing.
+package log
+
+type Level int
+
+type Context struct {
+	level Level
+}
+
+func Debug() *Context { return &Context{} }
+func Info() *Context  { return &Context{} }
+func Error() *Context { return &Context{} }
+
+func (ctx *Context) Int(key string, value int) *Context       { return ctx }
+func (ctx *Context) String(key string, value string) *Context { return ctx }
+func (ctx *Context) Error(key string, value error) *Context   { return ctx }
+func (ctx *Context) Any(key string, value any) *Context       { return ctx }
+func (ctx *Context) With(keysAndValues ...any) *Context       { return ctx }
+func (ctx *Context) Print(s string)                           {}
+func (ctx *Context) Printf(format string, args ...any)        {}
+
+func Printf(level Level, format string, args ...any) {}
+func With(keysAndValues ...any) *Context             { return &Context{} }
@@ -0,0 +1,89 @@
+package a
+
+import "sync"
+
+type counter struct {
+	mu sync.RWMutex
+	//@mod:guardedby mu
+	n int
+	//@mod:guardedby lock
+	m int // want `field m is guarded by lock which is not a sync.Mutex or sync.RWMutex field`
+}
+
+func newCounter() *counter {
+	// It's ok because of the value is newly allocated
+	return &counter{n: 1}
+}
+
+func (c *counter) inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// It's ok
+	c.n++
+}
+
+func (c *counter) get() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	// It's ok
+	return c.n
+}
+
+func (c *counter) set(n int) {
+	c.mu.RLock()
+	c.n = n // want `field n is written while mu is only read-locked`
+	c.mu.RUnlock()
+}
+
+func (c *counter) reset() {
+	c.mu.Lock()
+	c.mu.Unlock()
+	c.n = 0 // want `field n is written without holding mu`
+}
+
+func (c *counter) peek(force bool) int {
+	if force {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+	return c.n // want `field n is read without holding mu`
+}
+
+func (c *counter) resetLocked() {
+	// It's ok because of the method is called with mu held
+	c.n = 0
+}
+
+func (c *counter) maybeUnlock(release bool) {
+	c.mu.Lock()
+	if release {
+		c.mu.Unlock()
+	}
+	c.n = 1 // want `field n is written without holding mu`
+}
+
+func (c *counter) loop(n int) {
+	for i := 0; i < n; i++ {
+		c.mu.Lock()
+		// It's ok because of the lock is acquired again in each iteration
+		c.n++
+		c.mu.Unlock()
+	}
+}
+
+func (c counter) value() int {
+	return c.n // want `field n is read without holding mu`
+}
+
+func (c counter) lockedValue() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	// It's ok
+	return c.n
+}
+
+func (c counter) unlockedValue() int {
+	c.mu.RLock()
+	c.mu.RUnlock()
+	return c.n // want `field n is read without holding mu`
+}
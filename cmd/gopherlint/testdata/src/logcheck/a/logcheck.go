@@ -0,0 +1,39 @@
+package a
+
+import (
+	"errors"
+
+	"github.com/gopherd/log"
+)
+
+func _(id int, name string) {
+	err := errors.New("failed")
+
+	log.Info().Int("id", id).String("id", name).Print("login") // want `duplicate log field key "id"`
+
+	log.Info().String(name, "x").Print("login") // want `non-constant key of log field String`
+
+	log.Error().String("error", err.Error()).Print("login") // want `error value passed as string to String, pass the error itself`
+
+	log.With("id", id, "name").Print("login") // want `odd number of key/value arguments in call to With`
+
+	log.Info().With("id", id, name, 1).Print("login") // want `non-constant key in call to With`
+
+	log.With("id", id, "id", 2).Print("login") // want `duplicate log field key "id"`
+
+	log.With("error", err.Error()).Print("login") // want `error value passed as string to With, pass the error itself`
+
+	log.Info().Printf("%s logged in with %d", name) // want `Printf format "%s logged in with %d" reads 2 args, but call has 1 args`
+
+	log.Printf(0, "%d%%", id, name) // want `Printf format "%d%%" reads 1 args, but call has 2 args`
+
+	// It's ok
+	log.Info().Int("id", id).String("name", name).Error("error", err).Print("login")
+	log.Info().Int("id", id).Print("login")
+	log.Info().Int("id", id).Print("login")
+	log.Info().Printf("%s logged in with %*d", name, 4, id)
+	log.Info().Printf("%[1]s %[1]s", name)
+	log.With("id", id, "name", name).Printf("%v", err)
+	kvs := []any{"id", id}
+	log.With(kvs...).Print("login")
+}
@@ -0,0 +1,21 @@
+// A file scope directive takes precedence over a package scope directive with
+// the same name.
+//
+//@mod:lint-disable nilnil
+package a
+
+var Size = 1 // want Size:"ModifierFact: lint-disable nilnil, final$"
+
+//@mod:lint-disable ctxcheck
+var (
+	Width = 1 // want Width:"ModifierFact: lint-disable ctxcheck, final$"
+
+	//@mod:lint-disable typednil
+	Height = 2 // want Height:"ModifierFact: lint-disable typednil, final$"
+
+	//@mod:!final
+	Depth = 3 // want Depth:"ModifierFact: lint-disable ctxcheck$"
+)
+
+//@mod:internal a/...
+func Open() {} // want Open:"ModifierFact: internal a/..., lint-disable nilnil, final$"
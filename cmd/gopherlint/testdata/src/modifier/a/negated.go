@@ -0,0 +1,16 @@
+// Declarations of this file are not final.
+//
+//@mod:!final
+package a
+
+var Counter = 0 // want Counter:"ModifierFact: lint-disable unusedresult$"
+
+//@mod:final
+var Max = 10 // want Max:"ModifierFact: final, lint-disable unusedresult$"
+
+// It's ok because of all directives inherited by reset are negated
+//
+//@mod:!lint-disable
+func reset() {
+	Counter = 0
+}
@@ -0,0 +1,15 @@
+// Every top-level declaration of the package is final, and unusedresult is
+// disabled for it.
+//
+//@mod:package final
+//@mod:package lint-disable unusedresult
+package a
+
+var Limit = 1 // want Limit:"ModifierFact: final, lint-disable unusedresult$"
+
+type Config struct { // want Config:"ModifierFact: final, lint-disable unusedresult$"
+	// It's ok because of package scope directives apply to top-level declarations only
+	Name string
+}
+
+func Load() {} // want Load:"ModifierFact: final, lint-disable unusedresult$"
@@ -0,0 +1,4 @@
+package a
+
+// It's ok because of package scope directives apply to files without directives
+var Count = 0 // want Count:"ModifierFact: final, lint-disable unusedresult$"
@@ -0,0 +1,84 @@
+package a
+
+import (
+	"errors"
+	"io"
+)
+
+type user struct {
+	name string
+}
+
+var errNotFound = errors.New("not found")
+
+func find(name string) (*user, error) {
+	if name == "" {
+		return nil, nil // want `return of nil \*user with nil error`
+	}
+	if name == "guest" {
+		// It's ok
+		return nil, errNotFound
+	}
+	return &user{name: name}, nil
+}
+
+func lookup(users map[string]*user, name string) (*user, error) {
+	var u *user
+	var err error
+	if name == "" {
+		return u, err // want `return of nil \*user with nil error`
+	}
+	if x, ok := users[name]; ok {
+		u = x
+	}
+	// It's ok because of u may be non-nil
+	return u, err
+}
+
+func named(name string) (u *user, err error) {
+	if name == "" {
+		return // want `return of nil \*user with nil error`
+	}
+	return &user{name: name}, nil
+}
+
+func reader(r io.Reader) (io.Reader, error) {
+	if r == nil {
+		return nil, nil // want `return of nil io.Reader with nil error`
+	}
+	return r, nil
+}
+
+func tags() (map[string]string, error) {
+	var m map[string]string
+	return m, nil // want `return of nil map\[string\]string with nil error`
+}
+
+// It's ok because of the results are not (pointer-like, error)
+func count() (int, error) {
+	return 0, nil
+}
+
+// It's ok because of the function is allowed to return nil, nil
+//
+//@mod:nilnil
+func findOptional(name string) (*user, error) {
+	if name == "" {
+		return nil, nil
+	}
+	get := func() (*user, error) {
+		return nil, nil
+	}
+	return get()
+}
+
+// It's ok because of the function is listed in flag -nilnil.allow
+func findAllowed(name string) (*user, error) {
+	return nil, nil
+}
+
+func _() {
+	_ = func() (*user, error) {
+		return nil, nil // want `return of nil \*user with nil error`
+	}
+}
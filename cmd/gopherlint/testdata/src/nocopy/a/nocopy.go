@@ -0,0 +1,61 @@
+package a
+
+//@mod:nocopy
+type buffer struct {
+	data []byte
+}
+
+//@mod:nocopy
+type token struct {
+	id int
+}
+
+type pool struct {
+	buf buffer
+}
+
+func (b *buffer) reset() { b.data = b.data[:0] }
+
+func (b buffer) size() int { return len(b.data) } // want `receiver passes value of nocopy type: buffer`
+
+func usePool(p pool) {} // want `parameter passes value of nocopy type: pool contains buffer`
+
+func newBuffer() buffer { return buffer{} }
+
+func (p *pool) buffer() buffer {
+	return p.buf // want `return copies value of nocopy type: buffer`
+}
+
+func _() {
+	// It's ok because of composite literal is a new value
+	var b = buffer{}
+	// It's ok because of function result is a new value
+	b = newBuffer()
+
+	c := b // want `assignment copies value of nocopy type: buffer`
+	c.reset()
+
+	var p = &pool{}
+	usePool(*p) // want `call passes value of nocopy type: pool contains buffer`
+
+	// It's ok
+	var q = p
+	_ = q
+
+	for _, x := range []buffer{} { // want `range var copies value of nocopy type: buffer`
+		x.reset()
+	}
+
+	for k := range map[token]int{} { // want `range var copies value of nocopy type: token`
+		_ = k.id
+	}
+
+	// It's ok because of values assigned to blank identifier are not copied
+	_ = b
+	for range []buffer{} {
+	}
+
+	var d buffer
+	_, d = b, b // want `assignment copies value of nocopy type: buffer`
+	d.reset()
+}
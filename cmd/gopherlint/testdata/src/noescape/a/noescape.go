@@ -0,0 +1,49 @@
+package a
+
+var retained []*int
+
+type holder struct {
+	p *int
+}
+
+//@mod:noescape p
+func noescape(p *int, h *holder, ch chan *int) {
+	// It's ok
+	q := p
+	_ = q
+
+	retained = append(retained, p) // want `noescape parameter p escapes: stored in a heap location`
+
+	h.p = p // want `noescape parameter p escapes: stored in a heap location`
+
+	ch <- p // want `noescape parameter p escapes: sent on a channel`
+
+	go func() {
+		*p = 1 // want `noescape parameter p escapes: used by a goroutine`
+	}()
+}
+
+//@mod:noescape x
+func unknownParam(p *int) {} // want `unknown parameter x in noescape directive of unknownParam`
+
+var global *int
+
+func param(
+	//@mod:noescape
+	p *int,
+	h *holder, //@mod:noescape is a trailing comment of h, not a directive of r
+	r *int,
+) {
+	// It's ok because of h and r are not noescape parameters
+	global = r
+	h.p = r
+
+	q := p
+	var s = []*int{q}
+	retained = s // want `noescape parameter p escapes: stored in a heap location`
+	global = q // want `noescape parameter p escapes: stored in a heap location`
+
+	go func() {
+		_ = q // want `noescape parameter p escapes: used by a goroutine`
+	}()
+}
@@ -0,0 +1,118 @@
+package a
+
+import (
+	"reflect"
+
+	"protomsg/pb"
+)
+
+func name(u *pb.User) string {
+	return u.Name // want `field Name of possibly nil protobuf message is read directly, use GetName\(\)`
+}
+
+func avatar(u *pb.User) string {
+	return u.Profile.Avatar // want `field Profile of possibly nil protobuf message is read directly, use GetProfile\(\)` `field Avatar of possibly nil protobuf message is read directly, use GetAvatar\(\)`
+}
+
+func _(u *pb.User) {
+	// It's ok because of the message is checked
+	if u != nil && u.Id > 0 {
+		_ = u.Name
+	}
+
+	// It's ok because of fields are written
+	u.Id = 1
+	u.Id++
+	_ = &u.Name
+
+	// It's ok
+	_ = u.GetName()
+	var v pb.User
+	_ = v.Name
+}
+
+func nameOrEmpty(u *pb.User) string {
+	// It's ok because of the message is checked by an early return
+	if u == nil {
+		return ""
+	}
+	return u.Name
+}
+
+func names(users []*pb.User) []string {
+	var result []string
+	for _, u := range users {
+		// It's ok
+		if u == nil || u.Id == 0 {
+			continue
+		}
+		result = append(result, u.Name)
+	}
+	return result
+}
+
+func _(u, v *pb.User) string {
+	if u == nil {
+		return ""
+	}
+	u = v
+	return u.Name // want `field Name of possibly nil protobuf message is read directly, use GetName\(\)`
+}
+
+func _(u *pb.User) string {
+	if u == nil {
+		println()
+	}
+	return u.Name // want `field Name of possibly nil protobuf message is read directly, use GetName\(\)`
+}
+
+func decode(u *pb.User) {}
+
+func _() string {
+	// It's ok because of u is only assigned new messages
+	u := &pb.User{}
+	decode(u)
+	if u.Name == "" {
+		u = new(pb.User)
+	}
+	return u.Name
+}
+
+func _(users []*pb.User) string {
+	u := &pb.User{}
+	if len(users) > 0 {
+		u = users[0]
+	}
+	return u.Name // want `field Name of possibly nil protobuf message is read directly, use GetName\(\)`
+}
+
+func copyUser(u pb.User) {} // want `parameter passes protobuf message pb.User by value`
+
+func _(u *pb.User, users []pb.User) {
+	v := *u // want `assignment copies protobuf message pb.User by value`
+	copyUser(v) // want `call passes protobuf message pb.User by value`
+
+	for _, x := range users { // want `range var copies protobuf message pb.User by value`
+		_ = x.Id
+	}
+
+	// It's ok because of composite literal is a new value
+	var w = pb.User{Id: 1}
+	_ = &w
+}
+
+func _(x, y *pb.User) bool {
+	// It's ok
+	if x == nil {
+		return false
+	}
+	// It's ok because of pointers are compared
+	return x == y
+}
+
+func _(x, y *pb.User) bool {
+	if *x != *y { // want `comparison of protobuf messages by !=, use proto.Equal`
+		return false
+	}
+	return reflect.DeepEqual(x, y) // want `comparison of protobuf messages by reflect.DeepEqual, use proto.Equal`
+}
@@ -0,0 +1,100 @@
+package a
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"pure/b"
+)
+
+var total int
+
+//@mod:sideeffect
+func record(x int) { total += x }
+
+func notAnnotated(x int) int { return x }
+
+//@mod:pure
+func square(x int) int { return x * x } // want square:"pureFact"
+
+//@mod:pure
+func compute(x int) int {
+	// It's ok because of strings is a pure package
+	_ = strings.Repeat("x", x)
+	// It's ok because of b.Add has been verified
+	x = b.Add(x, 1)
+	// It's ok
+	x = square(x)
+
+	total = x // want `pure function compute writes package-level variable total`
+
+	fmt.Println(x) // want `pure function compute performs I/O call fmt.Println`
+
+	record(x) // want `pure function compute calls function pure/a.record with side effects`
+
+	x = notAnnotated(x) // want `pure function compute calls non-pure function pure/a.notAnnotated`
+
+	x += b.Counter() // want `pure function compute calls non-pure function pure/b.Counter`
+	return x
+}
+
+//@mod:pure
+func impure(x int) int {
+	total = x // want `pure function impure writes package-level variable total`
+	return x
+}
+
+//@mod:pure
+func callsImpure(x int) int {
+	return impure(x) // want `pure function callsImpure calls non-pure function pure/a.impure`
+}
+
+//@mod:pure
+func even(x int) bool { // want even:"pureFact"
+	if x == 0 {
+		return true
+	}
+	return odd(x - 1)
+}
+
+//@mod:pure
+func odd(x int) bool { // want odd:"pureFact"
+	if x == 0 {
+		return false
+	}
+	return even(x - 1)
+}
+
+//@mod:pure
+func ranges(xs []int) {
+	for total = range xs { // want `pure function ranges writes package-level variable total`
+	}
+	for _, total = range xs { // want `pure function ranges writes package-level variable total`
+	}
+}
+
+var (
+	builder strings.Builder
+	values  []int
+	number  int
+)
+
+//@mod:pure
+func writesThroughCalls(x int) int {
+	builder.WriteString("x") // want `pure function writesThroughCalls modifies package-level variable builder by method WriteString`
+	sort.Ints(values)        // want `pure function writesThroughCalls calls non-pure function sort.Ints`
+	p := &number             // want `pure function writesThroughCalls references package-level variable number`
+	*p = x
+	return x
+}
+
+//@mod:pure
+func readsThroughCalls(x int) bool { // want readsThroughCalls:"pureFact"
+	// It's ok because of the builder is local
+	var local strings.Builder
+	local.WriteString("x")
+	// It's ok because of slices.Contains doesn't modify its arguments
+	return slices.Contains(values, x) && builder.Len() > local.Len()
+}
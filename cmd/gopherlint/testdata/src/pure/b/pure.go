@@ -0,0 +1,12 @@
+package b
+
+//@mod:pure
+func Add(x, y int) int { return x + y } // want Add:"pureFact"
+
+//@mod:pure
+func Counter() int {
+	counter++ // want `pure function Counter writes package-level variable counter`
+	return counter
+}
+
+var counter int
@@ -0,0 +1,9 @@
+//gopherlint:file-ignore unusedresult the whole file is ignored
+
+package a
+
+func _() {
+	var u user
+	// It's ok because of the file-ignore directive
+	u.self()
+}
@@ -0,0 +1,37 @@
+package a
+
+type user struct {
+}
+
+func (u *user) self() *user { return u }
+func (u *user) end()        {}
+
+//@mod:lint-disable unusedresult
+func lintDisabled() {
+	var u user
+	// It's ok because of lint-disable modifier of the function
+	u.self()
+}
+
+func _() {
+	var u user
+
+	u.self() // want `result of \(suppress/a.user\).self call not used`
+
+	// It's ok because of the trailing directive
+	u.self() //gopherlint:ignore unusedresult the result is not needed
+
+	// It's ok because of the directive suppresses the whole statement
+	//gopherlint:ignore unusedresult the results are not needed
+	if u.self() != nil {
+		u.self()
+		u.self()
+	}
+
+	//gopherlint:ignore unusedresult nothing to suppress // want `unused gopherlint:ignore directive for unusedresult`
+	u.end()
+
+	// It's ok because of the directive names other analyzers
+	//gopherlint:ignore final,nocopy the analyzers are not run
+	u.end()
+}
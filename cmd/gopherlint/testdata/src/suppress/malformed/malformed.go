@@ -0,0 +1,13 @@
+package malformed
+
+func _() {
+	// want +1 `malformed directive, expect "gopherlint:ignore analyzer\[,analyzer\.\.\.\] reason"`
+	//gopherlint:ignore unusedresult
+
+	//gopherlint:ignore nosuch the analyzer does not exist // want `unknown analyzer "nosuch" in gopherlint:ignore directive`
+
+	//gopherlint:file-ignore nosuch,unusedresult the analyzer does not exist // want `unknown analyzer "nosuch" in gopherlint:file-ignore directive`
+
+	// It's ok
+	//gopherlint:ignore unusedresult the analyzer exists
+}
@@ -0,0 +1,70 @@
+package a
+
+import (
+	"io"
+	"os"
+)
+
+type myError struct{}
+
+func (*myError) Error() string { return "my error" }
+
+func check(failed bool) error {
+	var err *myError
+	if failed {
+		err = &myError{}
+	}
+	return err // want `\*myError which may be nil is returned as error, the result is not nil even if it is`
+}
+
+func find() error {
+	var err *myError
+	return err // want `\*myError which may be nil is returned as error, the result is not nil even if it is`
+}
+
+func _(failed bool) {
+	var p *myError
+	var err error = p
+	if err == nil { // want `comparison of interface holding \*myError with nil is always false`
+		return
+	}
+	if failed {
+		err = &myError{}
+	}
+	if err != nil { // want `comparison of interface holding \*myError with nil is always true`
+		return
+	}
+}
+
+func _(failed bool) error {
+	// It's ok because of the returned value is never nil
+	err := &myError{}
+	if failed {
+		return err
+	}
+
+	// It's ok because of nil is returned by an untyped nil
+	var result error
+	if failed {
+		result = &myError{}
+	}
+	if result != nil {
+		return result
+	}
+	return nil
+}
+
+func _(failed bool) {
+	// It's ok because of interfaces holding non-nil pointers
+	var err error = &myError{}
+	if err != nil {
+		return
+	}
+	var w io.Writer = os.Stdout
+	if failed {
+		w = os.Stderr
+	}
+	if w == nil {
+		return
+	}
+}
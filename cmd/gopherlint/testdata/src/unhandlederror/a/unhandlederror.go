@@ -0,0 +1,81 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+func open() (*os.File, error) { return nil, errors.New("not implemented") }
+
+func _() {
+	f, err := open()
+	if err != nil { // want `error err is checked but not handled, execution continues`
+		log.Print(err)
+	}
+	f.Close()
+
+	if err := f.Sync(); err != nil { // want `error err is checked but not handled, execution continues`
+	}
+	f.Close()
+
+	// It's ok because of the function returns
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	// It's ok because of the program exits
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// It's ok because of the error is wrapped
+	if err != nil {
+		err = fmt.Errorf("open: %w", err)
+	}
+
+	var errs []error
+	// It's ok because of the error is collected
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	ch := make(chan error, 1)
+	// It's ok because of the error is sent
+	if err != nil {
+		ch <- err
+	}
+
+	// It's ok because of the else branch
+	if err != nil {
+		log.Print(err)
+	} else {
+		f.Close()
+	}
+
+	// It's ok because of the statement is the last one
+	if err := f.Close(); err != nil {
+		log.Print(err)
+	}
+}
+
+func _(files []*os.File) {
+	for _, f := range files {
+		// It's ok because of the loop continues
+		if err := f.Sync(); err != nil {
+			log.Print(err)
+			continue
+		}
+		f.Close()
+	}
+}
+
+func _(f *os.File) {
+	// It's ok because of nothing but a bare return follows
+	if err := f.Sync(); err != nil {
+		log.Print(err)
+	}
+	return
+}
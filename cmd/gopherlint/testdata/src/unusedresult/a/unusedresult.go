@@ -9,6 +9,9 @@ func (u *user) end()        {}
 func _() {
 	var u user
 
-	// result of (github.com/gopherd/tools/cmd/gopherlint/testdata/src/a.user).self call not used
-	u.self()
+	u.self() // want `result of \(unusedresult/a.user\).self call not used`
+
+	// It's ok
+	_ = u.self()
+	u.end()
 }
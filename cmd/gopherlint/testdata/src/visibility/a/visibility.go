@@ -0,0 +1,17 @@
+package a
+
+import (
+	"visibility/b"
+)
+
+func _() {
+	b.InternalFunc() // want `cannot use internal function b.InternalFunc outside visibility/b/\.\.\.`
+
+	// It's ok because package a is allowed
+	var t b.InternalType
+
+	t.Secret = 1 // want `cannot use internal field b.Secret outside visibility/b/\.\.\.`
+
+	// It's ok
+	t.Public = 1
+}
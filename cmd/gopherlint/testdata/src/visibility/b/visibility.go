@@ -0,0 +1,11 @@
+package b
+
+//@mod:internal
+func InternalFunc() {}
+
+//@mod:internal visibility/a
+type InternalType struct {
+	//@mod:internal
+	Secret int
+	Public int
+}
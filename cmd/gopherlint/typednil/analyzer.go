@@ -0,0 +1,156 @@
+package typednil
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const Doc = `check for typed nil values in interfaces.
+
+An interface holding a nil pointer, map, channel, function or slice is not
+nil. This analyzer reports
+
+	func f() error {
+		var err *MyError
+		if failed {
+			err = &MyError{}
+		}
+		return err // not nil even if err is nil
+	}
+
+where a pointer-like value which may be nil is returned as an interface result,
+and comparisons with nil of interfaces holding such values, which never hold.
+Values are tracked through local assignments within a function.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "typednil",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{buildssa.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	ssainput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	qualifier := types.RelativeTo(pass.Pkg)
+
+	for _, fn := range ssainput.SrcFuncs {
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				switch x := instr.(type) {
+				case *ssa.Return:
+					for _, v := range x.Results {
+						if mi, ok := v.(*ssa.MakeInterface); ok && isPointerLike(mi.X.Type()) && mayBeNil(mi.X, nil) && x.Pos().IsValid() {
+							pass.Reportf(x.Pos(), "%s which may be nil is returned as %s, the result is not nil even if it is",
+								types.TypeString(mi.X.Type(), qualifier), types.TypeString(mi.Type(), qualifier))
+						}
+					}
+				case *ssa.BinOp:
+					if x.Op != token.EQL && x.Op != token.NEQ || !x.Pos().IsValid() {
+						continue
+					}
+					var operand ssa.Value
+					switch {
+					case isNilConst(x.Y):
+						operand = x.X
+					case isNilConst(x.X):
+						operand = x.Y
+					default:
+						continue
+					}
+					if typ, isNil := heldType(operand, nil); typ != nil && isNil {
+						pass.Reportf(x.Pos(), "comparison of interface holding %s with nil is always %t",
+							types.TypeString(typ, qualifier), x.Op == token.NEQ)
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// isPointerLike reports whether nil is a valid value of non-interface type typ.
+func isPointerLike(typ types.Type) bool {
+	switch typ.Underlying().(type) {
+	case *types.Interface:
+		return false
+	case *types.Slice:
+		return true
+	}
+	return util.IsPointer(typ.Underlying())
+}
+
+func isNilConst(v ssa.Value) bool {
+	c, ok := v.(*ssa.Const)
+	return ok && c.IsNil()
+}
+
+// mayBeNil reports whether v is nil on some path: a nil constant, or a phi
+// node with such an edge.
+func mayBeNil(v ssa.Value, visited map[*ssa.Phi]bool) bool {
+	switch v := v.(type) {
+	case *ssa.Const:
+		return v.IsNil()
+	case *ssa.ChangeType:
+		return mayBeNil(v.X, visited)
+	case *ssa.Phi:
+		if visited[v] {
+			return false
+		}
+		if visited == nil {
+			visited = make(map[*ssa.Phi]bool)
+		}
+		visited[v] = true
+		for _, edge := range v.Edges {
+			if mayBeNil(edge, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// heldType returns the pointer-like type held by interface v if v is always
+// made from a value of the type, or nil. It also reports whether the value
+// held may be nil.
+func heldType(v ssa.Value, visited map[*ssa.Phi]bool) (types.Type, bool) {
+	switch v := v.(type) {
+	case *ssa.MakeInterface:
+		if isPointerLike(v.X.Type()) {
+			return v.X.Type(), mayBeNil(v.X, nil)
+		}
+	case *ssa.Phi:
+		if visited[v] {
+			return nil, false
+		}
+		if visited == nil {
+			visited = make(map[*ssa.Phi]bool)
+		}
+		visited[v] = true
+		var held types.Type
+		var isNil bool
+		for _, edge := range v.Edges {
+			if visited[asPhi(edge)] {
+				continue // a loop
+			}
+			typ, edgeNil := heldType(edge, visited)
+			if typ == nil || held != nil && !types.Identical(typ, held) {
+				return nil, false
+			}
+			held = typ
+			isNil = isNil || edgeNil
+		}
+		return held, isNil
+	}
+	return nil, false
+}
+
+func asPhi(v ssa.Value) *ssa.Phi {
+	phi, _ := v.(*ssa.Phi)
+	return phi
+}
@@ -0,0 +1,142 @@
+package unhandlederror
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/gopherd/tools/cmd/gopherlint/util"
+)
+
+const Doc = `check for errors which are checked but not handled.
+
+This analyzer reports statements like
+
+	if err != nil {
+		log.Error(err)
+	}
+
+followed by other statements, whose body neither interrupts control flow by
+return, break, continue, goto, panic or a Fatal/Exit-style call, nor assigns,
+wraps or sends the error, so execution silently continues with invalid state.
+An if statement with an else branch, at the end of a block or followed only by
+a bare return is not reported.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "unhandlederror",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{
+		(*ast.BlockStmt)(nil),
+		(*ast.CaseClause)(nil),
+		(*ast.CommClause)(nil),
+	}, func(n ast.Node) {
+		var list []ast.Stmt
+		switch x := n.(type) {
+		case *ast.BlockStmt:
+			list = x.List
+		case *ast.CaseClause:
+			list = x.Body
+		case *ast.CommClause:
+			list = x.Body
+		}
+		for i := 0; i < len(list); i++ {
+			ifStmt, ok := list[i].(*ast.IfStmt)
+			if !ok || ifStmt.Else != nil || isLast(list[i+1:]) {
+				continue
+			}
+			errObj := checkedError(pass, ifStmt.Cond)
+			if errObj == nil || handled(pass, ifStmt.Body, errObj) {
+				continue
+			}
+			pass.Reportf(ifStmt.Pos(), "error %s is checked but not handled, execution continues", errObj.Name)
+		}
+	})
+	return nil, nil
+}
+
+// isLast reports whether nothing continues after statements followed by
+// list, i.e. list is empty or a bare return.
+func isLast(list []ast.Stmt) bool {
+	for _, stmt := range list {
+		switch x := stmt.(type) {
+		case *ast.EmptyStmt:
+		case *ast.ReturnStmt:
+			return len(x.Results) == 0
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkedError returns the error variable of condition "err != nil" or "nil != err".
+func checkedError(pass *analysis.Pass, cond ast.Expr) *ast.Ident {
+	binary, ok := util.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || binary.Op != token.NEQ {
+		return nil
+	}
+	x, y := util.Unparen(binary.X), util.Unparen(binary.Y)
+	if util.IsNil(pass, x) {
+		x, y = y, x
+	}
+	if !util.IsNil(pass, y) {
+		return nil
+	}
+	ident, ok := x.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if typ := pass.TypesInfo.TypeOf(ident); typ == nil || !util.IsError(typ) {
+		return nil
+	}
+	return ident
+}
+
+// handled reports whether body interrupts control flow, or assigns, wraps
+// or sends the error err.
+func handled(pass *analysis.Pass, body *ast.BlockStmt, err *ast.Ident) bool {
+	obj := pass.TypesInfo.ObjectOf(err)
+	var uses = func(exprs ...ast.Expr) bool {
+		var found bool
+		for _, expr := range exprs {
+			ast.Inspect(expr, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == obj {
+					found = true
+				}
+				return !found
+			})
+		}
+		return found
+	}
+	var result bool
+	ast.Inspect(body, func(n ast.Node) bool {
+		if result {
+			return false
+		}
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			return false // statements of a closure don't affect the enclosing function
+		case *ast.AssignStmt:
+			// the error is assigned, e.g. "e.err = err", "errs = append(errs, err)", or reset
+			result = uses(x.Lhs...) || uses(x.Rhs...)
+		case *ast.ValueSpec:
+			result = uses(x.Values...)
+		case *ast.SendStmt:
+			result = uses(x.Value)
+		case ast.Stmt:
+			result = util.IsInterruptedStmt(pass, x)
+		}
+		return !result
+	})
+	return result
+}
@@ -0,0 +1,21 @@
+package unusedresult_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/gopherd/tools/cmd/gopherlint/unusedresult"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata, err := filepath.Abs("../testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unusedresult.Analyzer.Flags.Set("types", "*unusedresult/a.user"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, unusedresult.Analyzer, "unusedresult/...")
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// A CopyReporter reports a value of type typ copied at pos, what describes
+// how it's copied, e.g. "assignment copies". It ignores types which may be
+// copied.
+type CopyReporter func(pos token.Pos, what string, typ types.Type)
+
+// CheckCopy calls report if evaluating expr copies an existing value.
+// Composite literals and function results are new values, not copies.
+func CheckCopy(pass *analysis.Pass, expr ast.Expr, what string, report CopyReporter) {
+	expr = Unparen(expr)
+	switch x := expr.(type) {
+	case *ast.CompositeLit:
+		return // a new value, not a copy
+	case *ast.CallExpr:
+		if !pass.TypesInfo.Types[x.Fun].IsType() {
+			return // a function result, not a copy
+		}
+	}
+	if typ := pass.TypesInfo.TypeOf(expr); typ != nil {
+		report(expr.Pos(), what, typ)
+	}
+}
+
+// CheckAssign calls report for each value copied by stmt. A value assigned
+// to the blank identifier is not a copy.
+func CheckAssign(pass *analysis.Pass, stmt *ast.AssignStmt, what string, report CopyReporter) {
+	for i, expr := range stmt.Rhs {
+		if len(stmt.Lhs) == len(stmt.Rhs) && isBlank(stmt.Lhs[i]) {
+			continue
+		}
+		CheckCopy(pass, expr, what, report)
+	}
+}
+
+// CheckRange calls report for the key and value of stmt which are copied.
+func CheckRange(pass *analysis.Pass, stmt *ast.RangeStmt, what string, report CopyReporter) {
+	for _, expr := range []ast.Expr{stmt.Key, stmt.Value} {
+		if expr == nil || isBlank(expr) {
+			continue
+		}
+		if typ := pass.TypesInfo.TypeOf(expr); typ != nil {
+			report(expr.Pos(), what, typ)
+		}
+	}
+}
+
+func isBlank(expr ast.Expr) bool {
+	ident, ok := Unparen(expr).(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// CheckFields calls report for each field of fields, e.g. parameters, which
+// are passed by value.
+func CheckFields(pass *analysis.Pass, fields *ast.FieldList, what string, report CopyReporter) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		if typ := pass.TypesInfo.TypeOf(field.Type); typ != nil {
+			report(field.Type.Pos(), what, typ)
+		}
+	}
+}
+
+// IsBuiltinOrConversion reports whether call is a call of a builtin function or a conversion.
+func IsBuiltinOrConversion(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fun := Unparen(call.Fun)
+	if pass.TypesInfo.Types[fun].IsType() {
+		return true
+	}
+	if ident, ok := fun.(*ast.Ident); ok {
+		_, ok := pass.TypesInfo.Uses[ident].(*types.Builtin)
+		return ok
+	}
+	return false
+}
+
+// TypeString returns the string of typ qualified by package names, types of
+// the analyzed package are not qualified.
+func TypeString(pass *analysis.Pass, typ types.Type) string {
+	return types.TypeString(typ, func(pkg *types.Package) string {
+		if pkg == pass.Pkg {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
@@ -159,3 +159,9 @@ func IsInterruptedStmt(pass *analysis.Pass, stmt ast.Stmt) bool {
 func IsProtobufFile(filename string) bool {
 	return strings.HasSuffix(filename, ".pb.go")
 }
+
+// IsGeneratedFile reports whether file has a "// Code generated ... DO NOT EDIT." comment
+// or filename is a protobuf file.
+func IsGeneratedFile(filename string, file *ast.File) bool {
+	return ast.IsGenerated(file) || IsProtobufFile(filename)
+}
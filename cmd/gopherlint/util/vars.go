@@ -0,0 +1,73 @@
+package util
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// IsConstant reports whether variable obj of a basic type is initialized by constant value.
+func IsConstant(pass *analysis.Pass, obj types.Object, value ast.Expr) bool {
+	if obj == nil {
+		return false
+	}
+	if _, ok := obj.Type().Underlying().(*types.Basic); !ok {
+		return false
+	}
+	return pass.TypesInfo.Types[value].Value != nil
+}
+
+// WrittenVars returns variables of the package which are assigned, referenced
+// or used as pointer receivers after declaration.
+func WrittenVars(pass *analysis.Pass, inspect *inspector.Inspector) map[types.Object]bool {
+	var written = make(map[types.Object]bool)
+	var mark = func(expr ast.Expr) {
+		switch x := Unparen(expr).(type) {
+		case *ast.Ident:
+			written[pass.TypesInfo.ObjectOf(x)] = true
+		case *ast.SelectorExpr:
+			written[pass.TypesInfo.ObjectOf(x.Sel)] = true
+		}
+	}
+	inspect.Preorder([]ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.IncDecStmt)(nil),
+		(*ast.RangeStmt)(nil),
+		(*ast.UnaryExpr)(nil),
+		(*ast.SelectorExpr)(nil),
+	}, func(n ast.Node) {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			if x.Tok == token.DEFINE {
+				return
+			}
+			for _, lhs := range x.Lhs {
+				mark(lhs)
+			}
+		case *ast.IncDecStmt:
+			mark(x.X)
+		case *ast.RangeStmt:
+			if x.Tok == token.ASSIGN {
+				mark(x.Key)
+				mark(x.Value)
+			}
+		case *ast.UnaryExpr:
+			if x.Op == token.AND {
+				mark(x.X)
+			}
+		case *ast.SelectorExpr:
+			selection := pass.TypesInfo.Selections[x]
+			if selection == nil || selection.Kind() != types.MethodVal {
+				return
+			}
+			sig := selection.Obj().Type().(*types.Signature)
+			if _, ok := sig.Recv().Type().(*types.Pointer); ok && !selection.Indirect() {
+				mark(x.X) // an implicit reference to call the method
+			}
+		}
+	})
+	return written
+}
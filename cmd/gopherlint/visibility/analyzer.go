@@ -0,0 +1,109 @@
+package visibility
+
+import (
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/gopherd/tools/cmd/gopherlint/modifier"
+)
+
+const directive = "internal"
+
+const Doc = `check for uses of identifiers restricted by @mod:internal outside the allowed packages.
+
+An exported function, type, field or variable annotated by
+
+	//@mod:internal [pattern...]
+
+can only be used by packages whose import paths match one of the patterns.
+A pattern is an import path in which "..." matches any string, e.g.
+"github.com/acme/svc/...". The declaring package and its sub-packages are
+allowed if no pattern is specified. The declaring package is always allowed.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "visibility",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{modifier.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	modifiers := pass.ResultOf[modifier.Analyzer].(*modifier.Result)
+	path := strings.TrimSuffix(pass.Pkg.Path(), "_test")
+
+	for ident, obj := range pass.TypesInfo.Uses {
+		if obj.Pkg() == nil || obj.Pkg() == pass.Pkg || obj.Pkg().Path() == path {
+			continue
+		}
+		m, ok := modifiers.Find(obj, directive)
+		if !ok {
+			continue
+		}
+		patterns := m.Args()
+		if len(patterns) == 0 {
+			patterns = []string{obj.Pkg().Path() + "/..."}
+		}
+		if isAllowed(patterns, path) {
+			continue
+		}
+		pass.Reportf(
+			ident.Pos(),
+			"cannot use internal %s %s outside %s",
+			kindOf(obj), qualifiedName(obj), strings.Join(patterns, ", "),
+		)
+	}
+	return nil, nil
+}
+
+func isAllowed(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern reports whether path matches pattern, "..." in pattern matches any string
+// and a trailing "/..." also matches the path without it.
+func matchPattern(pattern, path string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok && !strings.Contains(prefix, "...") {
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	}
+	if !strings.Contains(pattern, "...") {
+		return path == pattern
+	}
+	expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\.\.\.`, `.*`)
+	if strings.HasSuffix(expr, "/.*") {
+		expr = strings.TrimSuffix(expr, "/.*") + "(/.*)?"
+	}
+	matched, _ := regexp.MatchString("^"+expr+"$", path)
+	return matched
+}
+
+func kindOf(obj types.Object) string {
+	switch x := obj.(type) {
+	case *types.Func:
+		if x.Type().(*types.Signature).Recv() != nil {
+			return "method"
+		}
+		return "function"
+	case *types.TypeName:
+		return "type"
+	case *types.Const:
+		return "constant"
+	case *types.Var:
+		if x.IsField() {
+			return "field"
+		}
+		return "variable"
+	}
+	return "identifier"
+}
+
+func qualifiedName(obj types.Object) string {
+	return obj.Pkg().Name() + "." + obj.Name()
+}